@@ -15,9 +15,23 @@ import (
 const ENVTag = "xml"
 
 // LevelSeparator is used to separate the names from different struct levels.
-// This is hard coded here and cannot be changed or modified.
+// This is the default for ENV.Sep.
 const LevelSeparator = "_"
 
+// NestedSeparator may be used for ENV.Sep to keep single underscores inside
+// names: APP__DB__MAX_CONNS is the max_conns member of the db struct.
+const NestedSeparator = "__"
+
+// KeysVar is appended to a map member's variable name to declare the map keys, when
+// a key has the separator in it: APP_SERVER_KEYS=web_1,web_2. Keys are separated
+// by KeysSeparator. This is only needed for maps of structs, slices and maps; other
+// map values use the rest of the variable name as the key: APP_LABELS_team_name=x.
+// This is upper-cased unless ENV.Low is true.
+const (
+	KeysVar       = "keys"
+	KeysSeparator = ","
+)
+
 // ENVUnmarshaler allows custom unmarshaling on a custom type.
 // If your type implements this, it will be called and the logic stops there.
 type ENVUnmarshaler interface {
@@ -37,15 +51,54 @@ type ENV struct {
 	Tag string // Struct tag name.
 	Pfx string // ENV var prefix.
 	Low bool   // Set this false to avoid capitalizing variables.
+	// Sep separates the names from different struct levels, slice indexes and map keys.
+	// The default is LevelSeparator. Set NestedSeparator to use double underscores.
+	Sep string
+	// Split sets the delimiter for slices and maps of single values in one variable:
+	// APP_USERS=me,you,them. It applies to every slice and map when set, and to
+	// members with the `split` tag option when they do not provide a delimiter.
+	// The indexed form (APP_USERS_0) is always accepted, and replaces items.
+	Split string
+	// Sparse sets the policy for slice indexes after a missing index. The default
+	// ignores them. SparseFill fills the missing indexes with zero values.
+	Sparse Sparse
+	// Delete removes a slice item when its variable has this value: APP_USERS_1=<Delete>.
+	// The indexes of the other items do not change until every index is parsed.
+	// Nothing is deleted when this is empty.
+	Delete string
+	// Continue parsing after an invalid value and return every error in an Errors list.
+	// Each error in the list is a *FieldError or a *MissingError.
+	Continue bool
+	// Redact replaces the values of members with the `secret` tag option with
+	// the Redacted placeholder when marshaling, and in parse errors.
+	Redact bool
+	// Strict returns an *UnknownError listing every prefixed variable that was not
+	// parsed into a struct member. The struct is still fully parsed, so you may choose
+	// to log this error as a warning. Strict does nothing without a prefix.
+	Strict bool
+	// Naming names the env variables of struct members without a name in their
+	// struct tag. The default uses the upper-cased Go field name.
+	Naming Naming
+	// NameFunc replaces the Naming policy with your own function.
+	NameFunc NameFunc
+	// types are concrete types for interface members, added with Register.
+	types registry
 }
 
+// Redacted replaces secret values when ENV.Redact is true.
+const Redacted = "***"
+
 // Satify goconst.
 const (
-	base10 = 10
-	bits8  = 8
-	bits16 = 16
-	bits32 = 32
-	bits64 = 64
+	// baseAuto detects the base from a 0x, 0o, 0b or 0 prefix, and allows underscores.
+	baseAuto = 0
+	base10   = 10
+	bits8    = 8
+	bits16   = 16
+	bits32   = 32
+	bits64   = 64
+	// bits128 is the size of a complex128.
+	bits128 = 128
 )
 
 // The following is only used in tests, and perhaps externally.
@@ -0,0 +1,206 @@
+package cnfg
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+)
+
+/* This file contains the logic to list every env variable a struct type accepts. */
+
+// These struct tags provide descriptions for documentation.
+// ie. `xml:"timeout" desc:"How long to wait for a response."`.
+const (
+	DescTag = "desc"
+	HelpTag = "help"
+)
+
+// These placeholders are used in variable names for slice indexes and map keys.
+const (
+	IndexPlaceholder = "{n}"
+	KeyPlaceholder   = "{key}"
+)
+
+// VarInfo describes an env variable that a struct accepts.
+type VarInfo struct {
+	// Name is the env variable name. Slices and maps have placeholders in the name.
+	// ie. APP_SHELTER_PEOPLE_{n}_NAME or APP_MAP_{key}
+	Name string `json:"name"`
+	// Path is the Go path to the struct member. ie. Config.Shelter.People[{n}].Name
+	Path string `json:"path"`
+	// Type is the Go type of the value, without pointers.
+	Type string `json:"type"`
+	// Options are the raw options from the struct tag. ie. ["delenv", "default=1s"]
+	Options  []string `json:"options,omitempty"`
+	Default  string   `json:"default,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	Secret   bool     `json:"secret,omitempty"`
+	// Desc is the description from the `desc` struct tag, or the `help` tag.
+	Desc string `json:"desc,omitempty"`
+	// Values are the accepted values. Only used for the TypeVar of an interface member.
+	Values []string `json:"values,omitempty"`
+}
+
+// Describe lists every env variable the provided struct accepts, in the order
+// the struct members are declared. Unlike Marshal, this walks the type, not the
+// value, so members without values are included. Pass a struct or a pointer to one.
+func (e *ENV) Describe(i any) ([]VarInfo, error) {
+	typ := reflect.TypeOf(i)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, ErrInvalidInterface
+	}
+
+	if e.Tag == "" {
+		e.Tag = ENVTag
+	}
+
+	path := typ.Name()
+	if path == "" {
+		path = typ.String()
+	}
+
+	describe := &describer{
+		Low:   e.Low,
+		Tag:   e.Tag,
+		Seen:  make(map[reflect.Type]bool),
+		Types: e.types,
+		Namer: e.namer(),
+		Sep:   e.sep(),
+		Split: e.Split,
+	}
+	describe.Struct(typ, e.Pfx, path, false)
+
+	return describe.Vars, nil
+}
+
+type describer struct {
+	Low   bool                  // allow lowercase variables?
+	Tag   string                // struct tag to look for on struct members
+	Vars  []VarInfo             // output
+	Seen  map[reflect.Type]bool // struct types being described, to avoid infinite recursion
+	Types registry              // concrete types for interface members
+	Namer NameFunc              // names members without a tag name
+	Sep   string                // level separator
+	Split string                // default delimiter for slices and maps in one variable
+	Delim string                // delimiter for the current member's slices and maps
+}
+
+// Struct describes every member of a struct type.
+func (d *describer) Struct(typ reflect.Type, prefix, path string, secret bool) {
+	if d.Seen[typ] {
+		return // This struct contains itself.
+	}
+
+	d.Seen[typ] = true
+	defer delete(d.Seen, typ)
+
+	for idx := range typ.NumField() {
+		member := typ.Field(idx)
+		if !member.IsExported() {
+			continue
+		}
+
+		raw := member.Tag.Get(d.Tag)
+
+		name, opts := memberName(member, d.Tag, d.Low, d.Namer)
+		if name == "-" {
+			continue
+		}
+
+		info := VarInfo{
+			Default:  opts.Default,
+			Required: opts.Required,
+			Secret:   secret || opts.Secret,
+			Desc:     member.Tag.Get(DescTag),
+		}
+
+		if info.Desc == "" {
+			info.Desc = member.Tag.Get(HelpTag)
+		}
+
+		if split := strings.Split(raw, ","); len(split) > 1 {
+			info.Options = split[1:]
+		}
+
+		tag := strings.Trim(strings.Join([]string{prefix, name}, d.Sep), d.Sep)
+		delim := d.Delim
+		d.Delim = opts.delim(d.Split)
+		d.Anything(member.Type, tag, path+"."+member.Name, info)
+		d.Delim = delim
+	}
+}
+
+// Anything describes any type. Only types parsed from a single variable are listed.
+func (d *describer) Anything(typ reflect.Type, tag, path string, info VarInfo) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ.Kind() == reflect.Interface && d.Types[typ] != nil:
+		d.Concrete(typ, tag, path, info)
+	case typ.Kind() == reflect.Interface, typ.Kind() == reflect.Func,
+		typ.Kind() == reflect.Chan, typ.Kind() == reflect.UnsafePointer:
+		return // These are not parsed from env variables.
+	case isLeaf(typ):
+		info.Name, info.Path, info.Type = tag, path, typ.String()
+		d.Vars = append(d.Vars, info)
+	case typ.Kind() == reflect.Struct:
+		d.Struct(typ, tag, path, info.Secret)
+	case typ.Kind() == reflect.Slice, typ.Kind() == reflect.Array:
+		d.split(typ, tag, path, info)
+
+		if typ.Kind() == reflect.Slice {
+			d.special(tag, path, LenVar, "int", info)
+		}
+
+		d.Anything(typ.Elem(), tag+d.Sep+IndexPlaceholder, path+"["+IndexPlaceholder+"]", info)
+	case typ.Kind() == reflect.Map:
+		d.split(typ, tag, path, info)
+
+		if !d.Types.leaf(typ.Elem()) {
+			d.special(tag, path, KeysVar, "string", info)
+		}
+
+		d.Anything(typ.Elem(), tag+d.Sep+KeyPlaceholder, path+"["+KeyPlaceholder+"]", info)
+	}
+}
+
+// split describes the variable for a slice or map in one variable, if the member has a delimiter.
+func (d *describer) split(typ reflect.Type, tag, path string, info VarInfo) {
+	if d.Delim != "" && typ.Kind() != reflect.Array && d.Types.leaf(typ.Elem()) {
+		info.Name, info.Path, info.Type = tag, path, typ.String()
+		d.Vars = append(d.Vars, info)
+	}
+}
+
+// special describes a variable that describes a member, like KeysVar or LenVar.
+// The member's default and required options do not apply to it.
+func (d *describer) special(tag, path, name, typ string, info VarInfo) {
+	info.Name, info.Path, info.Type = specialVar(tag, d.Sep, name, d.Low), path+".("+name+")", typ
+	info.Default, info.Required = "", false
+	d.Vars = append(d.Vars, info)
+}
+
+// isLeaf returns true if a type is parsed from a single env variable.
+func isLeaf(typ reflect.Type) bool {
+	ptr := reflect.PointerTo(typ)
+	if ptr.Implements(reflect.TypeFor[ENVUnmarshaler]()) ||
+		ptr.Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) ||
+		ptr.Implements(reflect.TypeFor[encoding.BinaryUnmarshaler]()) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Struct, reflect.Map:
+		return false
+	case reflect.Slice, reflect.Array:
+		return typ.Elem().Kind() == reflect.Uint8
+	default:
+		return true
+	}
+}
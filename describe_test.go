@@ -0,0 +1,103 @@
+package cnfg_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type describePerson struct {
+	Name string `xml:"name,required"`
+	Age  *int   `xml:"age"`
+}
+
+type DescribeShelter struct {
+	Title  string            `xml:"title,default=home"`
+	People []*describePerson `xml:"people"`
+}
+
+type describeConfig struct {
+	*DescribeShelter `xml:"shelter"`
+	Timeout          cnfg.Duration               `xml:"timeout,delenv,default=1m"`
+	IP               net.IP                      `xml:"ip"`
+	Map              map[string]time.Duration    `xml:"map"`
+	Nested           map[string][]string         `xml:"nested"`
+	Token            string                      `xml:"token,secret"`
+	Creds            struct{ User, Pass string } `xml:"creds,secret"`
+	Self             *describeConfig             `xml:"self"`
+	Ignored          string                      `xml:"-"`
+	private          string
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	vars, err := (&cnfg.ENV{Pfx: "APP"}).Describe(&describeConfig{private: "unused"})
+	require.NoError(t, err)
+	assert.Equal([]cnfg.VarInfo{
+		{
+			Name: "APP_SHELTER_TITLE", Path: "describeConfig.DescribeShelter.Title", Type: "string",
+			Options: []string{"default=home"}, Default: "home",
+		},
+		{Name: "APP_SHELTER_PEOPLE_LEN", Path: "describeConfig.DescribeShelter.People.(len)", Type: "int"},
+		{
+			Name: "APP_SHELTER_PEOPLE_{n}_NAME", Path: "describeConfig.DescribeShelter.People[{n}].Name", Type: "string",
+			Options: []string{"required"}, Required: true,
+		},
+		{Name: "APP_SHELTER_PEOPLE_{n}_AGE", Path: "describeConfig.DescribeShelter.People[{n}].Age", Type: "int"},
+		{
+			Name: "APP_TIMEOUT", Path: "describeConfig.Timeout", Type: "cnfg.Duration",
+			Options: []string{"delenv", "default=1m"}, Default: "1m",
+		},
+		{Name: "APP_IP", Path: "describeConfig.IP", Type: "net.IP"},
+		{Name: "APP_MAP_{key}", Path: "describeConfig.Map[{key}]", Type: "time.Duration"},
+		{Name: "APP_NESTED_KEYS", Path: "describeConfig.Nested.(keys)", Type: "string"},
+		{Name: "APP_NESTED_{key}_LEN", Path: "describeConfig.Nested[{key}].(len)", Type: "int"},
+		{Name: "APP_NESTED_{key}_{n}", Path: "describeConfig.Nested[{key}][{n}]", Type: "string"},
+		{Name: "APP_TOKEN", Path: "describeConfig.Token", Type: "string", Options: []string{"secret"}, Secret: true},
+		{Name: "APP_CREDS_USER", Path: "describeConfig.Creds.User", Type: "string", Secret: true},
+		{Name: "APP_CREDS_PASS", Path: "describeConfig.Creds.Pass", Type: "string", Secret: true},
+	}, vars)
+
+	_, err = (&cnfg.ENV{}).Describe("not a struct")
+	require.ErrorIs(t, err, cnfg.ErrInvalidInterface)
+}
+
+func TestDescribeArray(t *testing.T) {
+	t.Parallel()
+
+	type describeArrays struct {
+		Hash   [32]byte   `xml:"hash"`
+		Coords [3]float64 `xml:"coord"`
+	}
+
+	vars, err := (&cnfg.ENV{}).Describe(describeArrays{})
+	require.NoError(t, err)
+	assert.Equal(t, []cnfg.VarInfo{
+		{Name: "HASH", Path: "describeArrays.Hash", Type: "[32]uint8"},
+		{Name: "COORD_{n}", Path: "describeArrays.Coords[{n}]", Type: "float64"},
+	}, vars)
+}
+
+func TestDescribeUnsupported(t *testing.T) {
+	t.Parallel()
+
+	type describeUnsupported struct {
+		Log   func(string)
+		Ch    chan int
+		Any   any
+		Err   error
+		Level int
+	}
+
+	vars, err := (&cnfg.ENV{}).Describe(describeUnsupported{})
+	require.NoError(t, err)
+	assert.Equal(t, []cnfg.VarInfo{{Name: "LEVEL", Path: "describeUnsupported.Level", Type: "int"}}, vars,
+		"functions, channels and interfaces without registered types must not be listed")
+}
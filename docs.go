@@ -0,0 +1,127 @@
+package cnfg
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+/* This file contains the logic to write documentation for a struct's env variables. */
+
+// DocFormat selects the output format for ENV.Document.
+type DocFormat int
+
+// These are the supported documentation formats.
+const (
+	DocMarkdown DocFormat = iota // Markdown table.
+	DocText                      // Plain text, aligned columns.
+	DocMan                       // Man page ENVIRONMENT section (roff).
+)
+
+// Document writes a reference of every env variable the provided struct accepts.
+// It includes the variable name, type, default, required flag and description.
+// Descriptions come from the `desc` (or `help`) struct tag.
+func (e *ENV) Document(output io.Writer, i any, format DocFormat) error {
+	vars, err := e.Describe(i)
+	if err != nil {
+		return err
+	}
+
+	var doc string
+
+	switch format {
+	case DocMarkdown:
+		doc = docMarkdown(vars)
+	case DocText:
+		doc = docText(vars)
+	case DocMan:
+		doc = docMan(vars)
+	default:
+		return fmt.Errorf("%w: %d", ErrDocFormat, format)
+	}
+
+	if _, err = io.WriteString(output, doc); err != nil {
+		return fmt.Errorf("writing documentation: %w", err)
+	}
+
+	return nil
+}
+
+func docMarkdown(vars []VarInfo) string {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	lines := []string{
+		"| Variable | Type | Default | Required | Description |",
+		"| --- | --- | --- | --- | --- |",
+	}
+
+	for _, v := range vars {
+		def := ""
+		if v.Default != "" {
+			def = "`" + cell.Replace(v.Default) + "`"
+		}
+
+		lines = append(lines, fmt.Sprintf("| `%s` | %s | %s | %s | %s |",
+			v.Name, cell.Replace(v.Type), def, yesNo(v.Required), cell.Replace(v.Desc)))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func docText(vars []VarInfo) string {
+	const padding = 2
+
+	var output strings.Builder
+
+	table := tabwriter.NewWriter(&output, 0, 0, padding, ' ', 0)
+	_, _ = fmt.Fprintln(table, "VARIABLE\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+
+	for _, v := range vars {
+		_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n",
+			v.Name, v.Type, v.Default, yesNo(v.Required), strings.ReplaceAll(v.Desc, "\n", " "))
+	}
+
+	_ = table.Flush() // A strings.Builder does not return errors.
+
+	return output.String()
+}
+
+func docMan(vars []VarInfo) string {
+	lines := []string{".SH ENVIRONMENT"}
+
+	for _, v := range vars {
+		details := "Type: " + v.Type + "."
+		if v.Default != "" {
+			details += " Default: " + v.Default + "."
+		}
+
+		if v.Required {
+			details += " Required."
+		}
+
+		lines = append(lines, ".TP", ".B "+roff(v.Name), roff(details))
+		if v.Desc != "" {
+			lines = append(lines, ".br", roff(v.Desc))
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// roff escapes text for a man page.
+func roff(text string) string {
+	text = strings.NewReplacer(`\`, `\e`, "-", `\-`, "\n", " ").Replace(text)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text // Do not let text become a control line.
+	}
+
+	return text
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
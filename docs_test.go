@@ -0,0 +1,51 @@
+package cnfg_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type docsConfig struct {
+	Timeout cnfg.Duration `xml:"timeout,default=1m" desc:"How long to wait | at most."`
+	Token   string        `xml:"token,required"     help:"API token for -the- service."`
+	Users   []string      `xml:"users"`
+}
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := &cnfg.ENV{Pfx: "APP"}
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, env.Document(buf, &docsConfig{}, cnfg.DocMarkdown))
+	assert.Equal("| Variable | Type | Default | Required | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `APP_TIMEOUT` | cnfg.Duration | `1m` | no | How long to wait \\| at most. |\n"+
+		"| `APP_TOKEN` | string |  | yes | API token for -the- service. |\n"+
+		"| `APP_USERS_LEN` | int |  | no |  |\n"+
+		"| `APP_USERS_{n}` | string |  | no |  |\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, env.Document(buf, &docsConfig{}, cnfg.DocText))
+	assert.Equal("VARIABLE       TYPE           DEFAULT  REQUIRED  DESCRIPTION\n"+
+		"APP_TIMEOUT    cnfg.Duration  1m       no        How long to wait | at most.\n"+
+		"APP_TOKEN      string                  yes       API token for -the- service.\n"+
+		"APP_USERS_LEN  int                     no        \n"+
+		"APP_USERS_{n}  string                  no        \n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, env.Document(buf, &docsConfig{}, cnfg.DocMan))
+	assert.Equal(".SH ENVIRONMENT\n"+
+		".TP\n.B APP_TIMEOUT\nType: cnfg.Duration. Default: 1m.\n.br\nHow long to wait | at most.\n"+
+		".TP\n.B APP_TOKEN\nType: string. Required.\n.br\nAPI token for \\-the\\- service.\n"+
+		".TP\n.B APP_USERS_LEN\nType: int.\n"+
+		".TP\n.B APP_USERS_{n}\nType: string.\n", buf.String())
+
+	require.ErrorIs(t, env.Document(buf, &docsConfig{}, cnfg.DocFormat(99)), cnfg.ErrDocFormat)
+	require.ErrorIs(t, env.Document(buf, "string", cnfg.DocText), cnfg.ErrInvalidInterface)
+}
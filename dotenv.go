@@ -0,0 +1,276 @@
+package cnfg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+/* This file contains the logic to read and write dotenv (.env) files. */
+
+// dotenvBare matches values that do not need quotes in a dotenv file.
+var dotenvBare = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+// ParseDotenv reads a dotenv (.env) file into Pairs. Pass the result to
+// ENV.UnmarshalMap to use the same struct for .env files and the environment.
+//
+// Supported syntax:
+//   - Blank lines and lines starting with # are ignored.
+//   - A leading `export ` is ignored.
+//   - Unquoted values end at the end of the line or at a ` #` comment.
+//   - Single quoted values are literal and may span multiple lines.
+//   - Double quoted values may span multiple lines and support escape sequences:
+//     \n \r \t \" \\ \$ and a backslash before a newline continues the line.
+//   - ${VAR} and $VAR are replaced in unquoted and double quoted values. Variables
+//     defined earlier in the file are used first, then the environment.
+func ParseDotenv(input io.Reader) (Pairs, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, fmt.Errorf("reading dotenv: %w", err)
+	}
+
+	dotenv := &dotenv{data: strings.ReplaceAll(string(data), "\r\n", "\n"), line: 1, pairs: Pairs{}}
+	if err := dotenv.parse(); err != nil {
+		return nil, fmt.Errorf("%w: line %d: %w", ErrDotenv, dotenv.line, err)
+	}
+
+	return dotenv.pairs, nil
+}
+
+// WriteDotenv writes the pairs as a dotenv (.env) file, sorted by name.
+// Values are double quoted and escaped when necessary, so they can be read
+// back in with ParseDotenv.
+func (p Pairs) WriteDotenv(output io.Writer) error {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+
+	var buf strings.Builder
+
+	for _, key := range p.SortedKeys() {
+		if val := p[key]; dotenvBare.MatchString(val) {
+			buf.WriteString(key + "=" + val + "\n")
+		} else {
+			buf.WriteString(key + `="` + escape.Replace(val) + "\"\n")
+		}
+	}
+
+	if _, err := io.WriteString(output, buf.String()); err != nil {
+		return fmt.Errorf("writing dotenv: %w", err)
+	}
+
+	return nil
+}
+
+// dotenv is a dotenv file parser.
+type dotenv struct {
+	data  string
+	pos   int
+	line  int
+	pairs Pairs
+}
+
+// Dotenv parse errors. These are wrapped in ErrDotenv.
+var (
+	errDotenvKey   = errors.New("missing variable name")
+	errDotenvEqual = errors.New("missing = after variable name")
+	errDotenvQuote = errors.New("missing closing quote")
+	errDotenvTrail = errors.New("unexpected characters after closing quote")
+)
+
+func (d *dotenv) parse() error {
+	for d.pos < len(d.data) {
+		line := d.data[d.pos:]
+		if end := strings.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end]
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			d.skipLine()
+			continue
+		}
+
+		d.pos += len(line) - len(strings.TrimLeft(line, " \t"))
+		if rest := d.data[d.pos:]; strings.HasPrefix(rest, "export ") || strings.HasPrefix(rest, "export\t") {
+			d.pos += len("export")
+			d.skipSpace()
+		}
+
+		if err := d.pair(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pair parses one KEY=value pair, starting at the key.
+func (d *dotenv) pair() error {
+	end := strings.IndexAny(d.data[d.pos:], "= \t\n")
+	if end < 0 {
+		end = len(d.data) - d.pos
+	}
+
+	key := d.data[d.pos : d.pos+end]
+	if key == "" {
+		return errDotenvKey
+	}
+
+	d.pos += end
+	d.skipSpace()
+
+	if d.pos >= len(d.data) || d.data[d.pos] != '=' {
+		return errDotenvEqual
+	}
+
+	d.pos++
+	d.skipSpace()
+
+	value, err := d.value()
+	if err != nil {
+		return err
+	}
+
+	d.pairs[key] = value
+
+	return nil
+}
+
+// value parses a value, starting after the equal sign and spaces.
+func (d *dotenv) value() (string, error) {
+	if d.pos >= len(d.data) {
+		return "", nil
+	}
+
+	switch d.data[d.pos] {
+	case '\'':
+		return d.quoted('\'')
+	case '"':
+		return d.quoted('"')
+	}
+
+	line := d.data[d.pos:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+
+	d.skipLine()
+
+	if idx := strings.Index(line, " #"); idx >= 0 {
+		line = line[:idx]
+	} else if idx = strings.Index(line, "\t#"); idx >= 0 {
+		line = line[:idx]
+	}
+
+	return d.expand(strings.Split(strings.TrimSpace(line), `\$`)), nil
+}
+
+// quoted parses a quoted value, starting at the opening quote.
+func (d *dotenv) quoted(quote byte) (string, error) {
+	var (
+		buf   strings.Builder
+		parts []string // The value is split at escaped dollar signs.
+	)
+
+	start := d.line // Report an unclosed quote where it opened.
+
+	for d.pos++; d.pos < len(d.data); d.pos++ {
+		char := d.data[d.pos]
+
+		switch {
+		case char == quote:
+			d.pos++
+			return d.closed(append(parts, buf.String()), quote)
+		case char == '\n':
+			d.line++
+		case char == '\\' && quote == '"' && d.pos+1 < len(d.data) && d.data[d.pos+1] == '$':
+			d.pos++
+			parts = append(parts, buf.String())
+			buf.Reset()
+
+			continue
+		case char == '\\' && quote == '"' && d.pos+1 < len(d.data):
+			d.pos++
+			buf.WriteString(d.escape(d.data[d.pos]))
+
+			continue
+		}
+
+		buf.WriteByte(char)
+	}
+
+	d.line = start
+
+	return "", errDotenvQuote
+}
+
+// closed finishes a quoted value, and makes sure nothing but a comment follows it.
+// Only double quoted values have more than one part.
+func (d *dotenv) closed(parts []string, quote byte) (string, error) {
+	d.skipSpace()
+
+	if d.pos < len(d.data) && d.data[d.pos] != '\n' && d.data[d.pos] != '#' {
+		return "", errDotenvTrail
+	}
+
+	d.skipLine()
+
+	if quote == '\'' {
+		return parts[0], nil
+	}
+
+	return d.expand(parts), nil
+}
+
+// escape returns the value for an escaped character in a double quoted value.
+func (d *dotenv) escape(char byte) string {
+	switch char {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case '\n':
+		d.line++
+		return "" // Line continuation.
+	case '"', '\\':
+		return string(char)
+	default:
+		return `\` + string(char)
+	}
+}
+
+// expand replaces ${VAR} and $VAR with the values of variables in each part of
+// a value, and joins the parts with the escaped dollar signs that separated them.
+func (d *dotenv) expand(parts []string) string {
+	for idx, part := range parts {
+		parts[idx] = os.Expand(part, func(name string) string {
+			if val, ok := d.pairs[name]; ok {
+				return val
+			}
+
+			return os.Getenv(name)
+		})
+	}
+
+	return strings.Join(parts, "$")
+}
+
+func (d *dotenv) skipSpace() {
+	for d.pos < len(d.data) && (d.data[d.pos] == ' ' || d.data[d.pos] == '\t') {
+		d.pos++
+	}
+}
+
+// skipLine moves to the beginning of the next line.
+func (d *dotenv) skipLine() {
+	if end := strings.IndexByte(d.data[d.pos:], '\n'); end >= 0 {
+		d.pos += end + 1
+		d.line++
+	} else {
+		d.pos = len(d.data)
+	}
+}
@@ -0,0 +1,93 @@
+package cnfg_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("DOTENV_FROM_ENV", "environment")
+
+	assert := assert.New(t)
+	input := `# comment line
+APP_NAME=golift
+export APP_TITLE = my title   # trailing comment
+	APP_EMPTY=
+APP_HASH=a#b
+APP_SINGLE='literal $APP_NAME \n # not a comment'
+APP_DOUBLE="tab\there \"quoted\" \\ \$APP_NAME ${APP_NAME}"
+APP_MULTI="line one
+line two"
+APP_RAW='first
+second'
+APP_CONT="one \
+two"
+APP_VARS=$APP_NAME-${DOTENV_FROM_ENV}-${APP_MISSING}
+APP_ESCAPED=cost \$5
+`
+
+	pairs, err := cnfg.ParseDotenv(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{
+		"APP_NAME":    "golift",
+		"APP_TITLE":   "my title",
+		"APP_EMPTY":   "",
+		"APP_HASH":    "a#b",
+		"APP_SINGLE":  `literal $APP_NAME \n # not a comment`,
+		"APP_DOUBLE":  "tab\there \"quoted\" \\ $APP_NAME golift",
+		"APP_MULTI":   "line one\nline two",
+		"APP_RAW":     "first\nsecond",
+		"APP_CONT":    "one two",
+		"APP_VARS":    "golift-environment-",
+		"APP_ESCAPED": "cost $5",
+	}, pairs)
+
+	for input, line := range map[string]string{
+		"APP_X='never closed\n\n": "line 1",
+		"A=1\nB=\"open\nC=3\n":    "line 2",
+		"\nAPP_X=\"a\" b":         "line 2",
+		"APP_X":                   "line 1",
+		"=value":                  "line 1",
+	} {
+		_, err := cnfg.ParseDotenv(strings.NewReader(input))
+		require.ErrorIs(t, err, cnfg.ErrDotenv, input)
+		assert.Contains(err.Error(), line, input)
+	}
+}
+
+func TestWriteDotenv(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	pairs := cnfg.Pairs{
+		"APP_B":     "simple",
+		"APP_A":     `pa$$word "with" \ and 'quotes'`,
+		"APP_C":     "multi\nline\r\nvalue",
+		"APP_EMPTY": "",
+		"APP_URL":   "https://golift.io/cnfg?a=b,c",
+	}
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, pairs.WriteDotenv(buf))
+	assert.Equal(`APP_A="pa\$\$word \"with\" \\ and 'quotes'"`+"\n"+
+		"APP_B=simple\n"+
+		`APP_C="multi\nline\r\nvalue"`+"\n"+
+		"APP_EMPTY=\n"+
+		`APP_URL="https://golift.io/cnfg?a=b,c"`+"\n", buf.String())
+
+	parsed, err := cnfg.ParseDotenv(buf)
+	require.NoError(t, err)
+	assert.Equal(pairs, parsed, "written values must be read back in unchanged")
+
+	buf.Reset()
+	pairs = cnfg.Pairs{"APP_NUL": "a\x00b$c"}
+	require.NoError(t, pairs.WriteDotenv(buf))
+	parsed, err = cnfg.ParseDotenv(buf)
+	require.NoError(t, err)
+	assert.Equal(pairs, parsed, "NUL bytes must not become dollar signs")
+}
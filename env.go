@@ -12,6 +12,19 @@ var (
 	ErrUnsupported      = errors.New("unsupported type, please report this if this type should be supported")
 	ErrInvalidByte      = errors.New("invalid byte")
 	ErrInvalidInterface = errors.New("can only unmarshal ENV into pointer to struct")
+	ErrRequired         = errors.New("required variables missing")
+	ErrUnknown          = errors.New("unknown variables found")
+	ErrArrayBounds      = errors.New("array index out of range")
+	ErrRegister         = errors.New("invalid type registration")
+	ErrUnknownType      = errors.New("unknown type name")
+	ErrInvalidSize      = errors.New("invalid byte size")
+	ErrRoundTrip        = errors.New("value did not survive a round trip")
+	ErrSplit            = errors.New("invalid delimited list")
+	ErrSliceIndex       = errors.New("slice index after a missing index")
+	ErrSliceLen         = errors.New("slice length is too large")
+	ErrDocFormat        = errors.New("unknown documentation format")
+	ErrDotenv           = errors.New("invalid dotenv")
+	ErrUnquotable       = errors.New("value cannot be quoted in this style")
 )
 
 // UnmarshalENV copies environment variables into configuration values.
@@ -34,9 +47,47 @@ func (e *ENV) Unmarshal(i any) (bool, error) {
 	}
 
 	// Save the current environment.
-	parse := &parser{Low: e.Low, Tag: e.Tag, Vals: MapEnvPairs(e.Pfx, os.Environ())}
+	return e.newParser(MapEnvPairs(e.Pfx, os.Environ())).Run(value, e.Pfx)
+}
+
+// newParser returns a parser for the provided pairs using the settings in ENV.
+func (e *ENV) newParser(vals Pairs) *parser {
+	return &parser{
+		Low:      e.Low,
+		Tag:      e.Tag,
+		Vals:     vals,
+		Continue: e.Continue,
+		Redact:   e.Redact,
+		Strict:   e.Strict,
+		Types:    e.types,
+		Namer:    e.namer(),
+		Sep:      e.sep(),
+		Split:    e.Split,
+		Sparse:   e.Sparse,
+		Delete:   e.Delete,
+	}
+}
 
-	return parse.Struct(value, e.Pfx)
+// newUnparser returns an unparser using the settings in ENV.
+func (e *ENV) newUnparser() *unparser {
+	return &unparser{
+		Low:    e.Low,
+		Tag:    e.Tag,
+		Redact: e.Redact,
+		Types:  e.types,
+		Namer:  e.namer(),
+		Sep:    e.sep(),
+		Split:  e.Split,
+	}
+}
+
+// sep returns the level separator.
+func (e *ENV) sep() string {
+	if e.Sep == "" {
+		return LevelSeparator
+	}
+
+	return e.Sep
 }
 
 // MarshalENV turns a data structure into an environment variable.
@@ -48,21 +99,30 @@ func MarshalENV(i any, prefix string) (Pairs, error) {
 
 // Marshal deconstructs a data structure into environment variable pairs.
 func (e *ENV) Marshal(i any) (Pairs, error) {
+	pairs, _, err := e.MarshalOrder(i)
+	return pairs, err
+}
+
+// MarshalOrder deconstructs a data structure into environment variable pairs,
+// and returns the variable names in struct order. That is the order the struct
+// members are declared in, with slice indexes in numeric order and map keys sorted.
+// Use the names with Pairs.EnvOrder to create stable output.
+func (e *ENV) MarshalOrder(i any) (Pairs, []string, error) {
 	value := reflect.ValueOf(i)
 	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
-		return nil, ErrInvalidInterface
+		return nil, nil, ErrInvalidInterface
 	}
 
 	if e.Tag == "" {
 		e.Tag = ENVTag
 	}
 
-	unparse := &unparser{Low: e.Low, Tag: e.Tag}
+	unparse := e.newUnparser()
 
 	pairs, err := unparse.DeconStruct(value, e.Pfx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return pairs, nil
+	return pairs, uniqueKeys(unparse.Keys), nil
 }
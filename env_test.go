@@ -2,6 +2,7 @@ package cnfg_test
 
 import (
 	"errors"
+	"math/big"
 	"net"
 	"net/url"
 	"os"
@@ -307,3 +308,59 @@ func testSpecialENV(t *testing.T, assert *assert.Assertions) {
 	assert.False(worked, "cannot parse an invalid time")
 	require.Error(t, err, "cannot parse an invalid time")
 }
+
+func TestComplexAndBig(t *testing.T) {
+	t.Parallel()
+
+	type complexNum complex64
+
+	type numbers struct {
+		C64   complex64  `xml:"c64"`
+		C128  complex128 `xml:"c128"`
+		Named complexNum `xml:"named"`
+		Int   *big.Int   `xml:"int"`
+		Float *big.Float `xml:"float"`
+		Rat   big.Rat    `xml:"rat"`
+	}
+
+	assert := assert.New(t)
+	pairs := map[string]string{
+		"APP_C64":   "1.5+2i",
+		"APP_C128":  "(-3-0.25i)",
+		"APP_NAMED": "4i",
+		"APP_INT":   "123456789012345678901234567890",
+		"APP_FLOAT": "0.12345678901234567890123456789",
+		"APP_RAT":   "1/3",
+	}
+	config := &numbers{}
+
+	worked, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.True(worked)
+	assert.Equal(complex64(1.5+2i), config.C64)
+	assert.Equal(-3-0.25i, config.C128)
+	assert.Equal(complexNum(4i), config.Named)
+	assert.Equal("123456789012345678901234567890", config.Int.String())
+	assert.Equal("0.12345678901234567890123456789", config.Float.Text('g', -1), "every digit must be kept")
+	assert.Equal("1/3", config.Rat.String())
+
+	marshaled, err := (&cnfg.ENV{Pfx: "APP"}).Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{
+		"APP_C64":   "(1.5+2i)",
+		"APP_C128":  "(-3-0.25i)",
+		"APP_NAMED": "(0+4i)",
+		"APP_INT":   "123456789012345678901234567890",
+		"APP_FLOAT": "0.12345678901234567890123456789",
+		"APP_RAT":   "1/3",
+	}, marshaled)
+
+	for name, val := range map[string]string{"APP_C64": "1+", "APP_INT": "12a", "APP_FLOAT": "x", "APP_RAT": "1/0"} {
+		_, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(map[string]string{name: val}, &numbers{})
+
+		var fieldErr *cnfg.FieldError
+
+		require.ErrorAs(t, err, &fieldErr, name)
+		assert.Equal(name, fieldErr.Var, "the error must include the variable name")
+	}
+}
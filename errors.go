@@ -0,0 +1,122 @@
+package cnfg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MissingError is returned when required struct members are not provided.
+// It contains the full name of every missing env variable, not just the first.
+type MissingError struct {
+	Vars []string
+}
+
+// Error lists the missing variables.
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrRequired, strings.Join(e.Vars, ", "))
+}
+
+// Unwrap allows errors.Is(err, ErrRequired).
+func (e *MissingError) Unwrap() error {
+	return ErrRequired
+}
+
+// FieldError is returned when an env variable cannot be parsed into a struct member.
+type FieldError struct {
+	Var   string       // Name of the env variable. ie. APP_SHELTER_PEOPLE_2_AGE
+	Path  string       // Go path to the struct member. ie. Config.Shelter.People[2].Age
+	Value string       // Raw value that failed to parse.
+	Type  reflect.Type // Type of the struct member.
+	Err   error        // Underlying parse error.
+}
+
+// Error returns the env variable name and the parse error.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Var, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// redactedError hides a secret value in the message of a parse error.
+// The original error is still available to errors.Is and errors.As.
+type redactedError struct {
+	err   error
+	value string
+}
+
+// Error returns the message of the innermost wrapped error, which is usually a sentinel
+// like strconv.ErrSyntax without the value. A quoted value in that message is replaced.
+func (e *redactedError) Error() string {
+	root := e.err
+	for errors.Unwrap(root) != nil {
+		root = errors.Unwrap(root)
+	}
+
+	msg := root.Error()
+	if e.value != "" {
+		msg = strings.ReplaceAll(msg, strconv.Quote(e.value), strconv.Quote(Redacted))
+	}
+
+	return "invalid secret value: " + msg
+}
+
+// Unwrap returns the original parse error.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Errors is returned when ENV.Continue is true and one or more errors occurred.
+// It works like the error returned by errors.Join; errors.Is and errors.As
+// inspect every error in the list.
+type Errors []error
+
+// Error returns every error message, separated by newlines.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the list of errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// UnknownError is returned when ENV.Strict is true and prefixed variables are
+// found that do not belong to any struct member. This is usually a typo.
+type UnknownError struct {
+	Vars []UnknownVar
+}
+
+// UnknownVar is an env variable that does not belong to any struct member.
+type UnknownVar struct {
+	Name    string // Name of the unknown variable.
+	Suggest string // Nearest valid variable name, if one is close.
+}
+
+// Error lists the unknown variables and their suggestions.
+func (e *UnknownError) Error() string {
+	msgs := make([]string, len(e.Vars))
+	for i, v := range e.Vars {
+		msgs[i] = v.Name
+		if v.Suggest != "" {
+			msgs[i] += " (did you mean " + v.Suggest + "?)"
+		}
+	}
+
+	return fmt.Sprintf("%v: %s", ErrUnknown, strings.Join(msgs, ", "))
+}
+
+// Unwrap allows errors.Is(err, ErrUnknown).
+func (e *UnknownError) Unwrap() error {
+	return ErrUnknown
+}
@@ -0,0 +1,90 @@
+package cnfg_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type errorPerson struct {
+	Name string `xml:"name"`
+	Age  int    `xml:"age"`
+}
+
+type errorConfig struct {
+	Timeout time.Duration `xml:"timeout"`
+	Shelter struct {
+		People []errorPerson   `xml:"people"`
+		Counts map[string]uint `xml:"counts"`
+	} `xml:"shelter"`
+	Port  uint16 `xml:"port"`
+	Token string `xml:"token,required"`
+}
+
+func TestFieldError(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	pairs := map[string]string{
+		"APP_SHELTER_PEOPLE_0_NAME": "me",
+		"APP_SHELTER_PEOPLE_1_NAME": "you",
+		"APP_SHELTER_PEOPLE_2_AGE":  "ten",
+	}
+
+	_, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, &errorConfig{})
+	require.ErrorIs(t, err, strconv.ErrSyntax)
+
+	var fieldErr *cnfg.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal("APP_SHELTER_PEOPLE_2_AGE", fieldErr.Var)
+	assert.Equal("errorConfig.Shelter.People[2].Age", fieldErr.Path)
+	assert.Equal("ten", fieldErr.Value)
+	assert.Equal(reflect.TypeFor[int](), fieldErr.Type)
+	assert.Contains(err.Error(), "APP_SHELTER_PEOPLE_2_AGE: ")
+}
+
+func TestContinue(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	pairs := map[string]string{
+		"APP_TIMEOUT":             "5 minutes",
+		"APP_SHELTER_COUNTS_dogs": "-1",
+		"APP_SHELTER_COUNTS_cats": "9",
+		"APP_PORT":                "99999",
+	}
+	config := &errorConfig{}
+
+	worked, err := (&cnfg.ENV{Pfx: "APP", Continue: true}).UnmarshalMap(pairs, config)
+	require.Error(t, err)
+	assert.False(worked)
+	assert.Equal(uint(9), config.Shelter.Counts["cats"], "parsing must continue after an error")
+
+	var errs cnfg.Errors
+
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 4, "every invalid value and the missing value must be returned")
+
+	paths := []string{}
+
+	for _, err := range errs[:3] {
+		var fieldErr *cnfg.FieldError
+
+		require.ErrorAs(t, err, &fieldErr)
+		paths = append(paths, fieldErr.Path)
+	}
+
+	assert.Equal([]string{
+		"errorConfig.Timeout", "errorConfig.Shelter.Counts[dogs]", "errorConfig.Port",
+	}, paths)
+	require.ErrorIs(t, err, cnfg.ErrRequired, "the missing variables must be included")
+	require.ErrorIs(t, errs[3], cnfg.ErrRequired)
+	assert.Equal(errors.Join(errs...).Error(), err.Error(), "the message must match errors.Join")
+}
@@ -3,6 +3,7 @@ package cnfg
 import (
 	"maps"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -15,17 +16,31 @@ const pairSize = 2
 // Get allows getting only specific env variables by prefix.
 // The prefix is trimmed before returning.
 func (p Pairs) Get(prefix string) Pairs {
+	return p.GetSep(prefix, LevelSeparator)
+}
+
+// GetSep is the same as Get, with a level separator other than LevelSeparator.
+func (p Pairs) GetSep(prefix, sep string) Pairs {
 	mapPairs := make(Pairs)
 
 	for k, v := range p {
 		if strings.HasPrefix(k, prefix) {
-			mapPairs[strings.SplitN(strings.TrimPrefix(k, prefix+LevelSeparator), LevelSeparator, pairSize)[0]] = v
+			mapPairs[strings.SplitN(strings.TrimPrefix(k, prefix+sep), sep, pairSize)[0]] = v
 		}
 	}
 
 	return mapPairs
 }
 
+// redact replaces every non-empty value with the Redacted placeholder.
+func (p Pairs) redact() {
+	for k, v := range p {
+		if v != "" {
+			p[k] = Redacted
+		}
+	}
+}
+
 // Set simply sets a value in a map.
 func (p Pairs) Set(k, v string) {
 	p[k] = v
@@ -58,7 +73,7 @@ func (e *ENV) UnmarshalMap(pairs map[string]string, i any) (bool, error) {
 		e.Tag = ENVTag
 	}
 
-	return (&parser{Tag: e.Tag, Vals: pairs}).Struct(value, e.Pfx)
+	return e.newParser(pairs).Run(value, e.Pfx)
 }
 
 // MapEnvPairs turns the pairs returned by os.Environ() into a map[string]string.
@@ -78,6 +93,7 @@ func MapEnvPairs(prefix string, pairs []string) Pairs {
 
 // Env turns the Pairs map into an envionrment variable slice.
 // This slice can be set to exec.Command().Env.
+// The order is random; use SortedEnv or EnvOrder for stable output.
 func (p Pairs) Env() []string {
 	output := make([]string, len(p))
 	i := 0
@@ -90,13 +106,44 @@ func (p Pairs) Env() []string {
 	return output
 }
 
-// Quoted turns the Pairs map into an envionrment variable slice that can be used by bash or other shells.
-func (p Pairs) Quoted() []string {
-	env := p.Env()
-	for i := range env {
-		s := strings.Split(env[i], "=")
-		env[i] = s[0] + `="` + s[1] + `"`
+// SortedKeys returns the variable names in lexical order.
+func (p Pairs) SortedKeys() []string {
+	return slices.Sorted(maps.Keys(p))
+}
+
+// SortedEnv turns the Pairs map into an environment variable slice sorted by name.
+func (p Pairs) SortedEnv() []string {
+	return p.EnvOrder(nil)
+}
+
+// EnvOrder turns the Pairs map into an environment variable slice in the order of
+// the provided keys, like the ones returned by ENV.MarshalOrder. Keys that are not
+// in the map are skipped. Pairs that are not in keys are added last in lexical order.
+func (p Pairs) EnvOrder(keys []string) []string {
+	output := make([]string, 0, len(p))
+	done := make(map[string]bool, len(p))
+
+	for _, k := range append(slices.Clip(keys), p.SortedKeys()...) {
+		if v, ok := p[k]; ok && !done[k] {
+			output = append(output, k+"="+v)
+			done[k] = true
+		}
+	}
+
+	return output
+}
+
+// uniqueKeys removes duplicate keys, keeping the first of each.
+func uniqueKeys(keys []string) []string {
+	output := make([]string, 0, len(keys))
+	done := make(map[string]bool, len(keys))
+
+	for _, k := range keys {
+		if !done[k] {
+			output = append(output, k)
+			done[k] = true
+		}
 	}
 
-	return env
+	return output
 }
@@ -114,3 +114,119 @@ func ExampleMapEnvPairs() {
 	// TESTAPP_ENVKEY2 some other env value
 	// ok: true, key: some env value, key2: some other env value, key3: add (or overwrite) a third value in code
 }
+
+func TestSortedEnv(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	pairs := cnfg.Pairs{"B": "2", "A": "1", "C_10": "x", "C_9": "y"}
+
+	assert.Equal([]string{"A", "B", "C_10", "C_9"}, pairs.SortedKeys())
+	assert.Equal([]string{"A=1", "B=2", "C_10=x", "C_9=y"}, pairs.SortedEnv())
+	assert.Equal([]string{"C_9=y", "C_10=x", "A=1", "B=2"}, pairs.EnvOrder([]string{"C_9", "MISSING", "C_10", "C_9"}),
+		"keys must be in the provided order, followed by the remaining keys sorted")
+}
+
+func TestMapKeysWithSeparator(t *testing.T) {
+	t.Parallel()
+
+	type host struct {
+		Addr string `xml:"addr"`
+		Port int    `xml:"port"`
+	}
+
+	type keysConfig struct {
+		Labels map[string]string `xml:"labels"`
+		Hosts  map[string]*host  `xml:"host"`
+		Lists  map[string][]int  `xml:"list"`
+	}
+
+	assert := assert.New(t)
+	config := &keysConfig{
+		Labels: map[string]string{"team_name": "ops", "app.kubernetes.io/name": "web", "x-y": "z"},
+		Hosts:  map[string]*host{"web_1.example.com": {Addr: "10.0.0.1", Port: 80}, "db": {Port: 5432}},
+		Lists:  map[string][]int{"a": {1, 2}},
+	}
+
+	pairs, err := (&cnfg.ENV{Pfx: "APP"}).Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{
+		"APP_LABELS_team_name":              "ops",
+		"APP_LABELS_app.kubernetes.io/name": "web",
+		"APP_LABELS_x-y":                    "z",
+		"APP_HOST_KEYS":                     "db,web_1.example.com",
+		"APP_HOST_db_ADDR":                  "",
+		"APP_HOST_db_PORT":                  "5432",
+		"APP_HOST_web_1.example.com_ADDR":   "10.0.0.1",
+		"APP_HOST_web_1.example.com_PORT":   "80",
+		"APP_LIST_a_0":                      "1",
+		"APP_LIST_a_1":                      "2",
+	}, pairs, "the key list is only needed for keys with the separator, and values that are not one variable")
+
+	parsed := &keysConfig{}
+	_, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, parsed)
+	require.NoError(t, err)
+	assert.Equal(config.Labels, parsed.Labels)
+	assert.Equal(config.Hosts["web_1.example.com"], parsed.Hosts["web_1.example.com"])
+	require.NoError(t, (&cnfg.ENV{Pfx: "APP", Strict: true}).RoundTrip(config))
+
+	parsed = &keysConfig{}
+	_, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(map[string]string{"APP_LABELS_KEYS": "v"}, parsed)
+	require.NoError(t, err)
+	assert.Equal(map[string]string{"KEYS": "v"}, parsed.Labels, "maps of single values must not have a key list")
+	require.NoError(t, (&cnfg.ENV{Pfx: "APP"}).RoundTrip(&keysConfig{Labels: map[string]string{"KEYS": "v"}}))
+}
+
+func TestNestedMaps(t *testing.T) {
+	t.Parallel()
+
+	type backend struct {
+		Host  string   `xml:"host"`
+		Port  int      `xml:"port"`
+		Paths []string `xml:"path"`
+	}
+
+	type nestedConfig struct {
+		Counts   map[string]map[string]int `xml:"count"`
+		Lists    map[string][]string       `xml:"list"`
+		Backends map[string]*backend       `xml:"backend"`
+	}
+
+	assert := assert.New(t)
+	config := &nestedConfig{
+		Counts: map[string]map[string]int{"a": {"x": 9, "z": 26}},
+		Backends: map[string]*backend{
+			"web": {Host: "web.local", Port: 80},
+			"old": {Host: "old.local"},
+		},
+	}
+	pairs := map[string]string{
+		"APP_COUNT_a_x":          "1",
+		"APP_COUNT_a_y":          "2",
+		"APP_COUNT_b_x":          "3",
+		"APP_LIST_a_0":           "one",
+		"APP_LIST_a_1":           "two",
+		"APP_LIST_b_0":           "three",
+		"APP_BACKEND_web_PORT":   "8080",
+		"APP_BACKEND_web_PATH_0": "/",
+		"APP_BACKEND_new_HOST":   "new.local",
+		"APP_BACKEND_old":        "",
+		"APP_BACKEND_web_HOSTS":  "typo",
+	}
+
+	_, err := (&cnfg.ENV{Pfx: "APP", Strict: true}).UnmarshalMap(pairs, config)
+
+	var unknown *cnfg.UnknownError
+
+	require.ErrorAs(t, err, &unknown, "variables under a map key must be checked like struct members")
+	assert.Equal([]cnfg.UnknownVar{{Name: "APP_BACKEND_web_HOSTS", Suggest: "APP_BACKEND_web_HOST"}}, unknown.Vars)
+	assert.Equal(map[string]map[string]int{"a": {"x": 1, "y": 2, "z": 26}, "b": {"x": 3}}, config.Counts,
+		"nested maps must start from the existing entries")
+	assert.Equal(map[string][]string{"a": {"one", "two"}, "b": {"three"}}, config.Lists)
+	assert.Equal(map[string]*backend{
+		"web": {Host: "web.local", Port: 8080, Paths: []string{"/"}},
+		"new": {Host: "new.local"},
+	}, config.Backends, "existing entries must be updated, and a blank entry variable must delete the entry")
+
+	require.NoError(t, (&cnfg.ENV{Pfx: "APP", Strict: true}).RoundTrip(config))
+}
@@ -0,0 +1,99 @@
+package cnfg
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+/* This file contains the logic to name env variables for struct members. */
+
+// Naming is the policy for naming env variables of struct members without a
+// name in their struct tag. Embedded structs without a tag name are always
+// flattened into their parent, and a tag name of "-" always skips the member.
+type Naming uint8
+
+// Naming policies for struct members without a tag name.
+const (
+	// NameField uses the Go field name: Name becomes NAME, and MaxSize becomes MAXSIZE.
+	NameField Naming = iota
+	// NameSnake splits the Go field name into words: MaxIdleConns becomes
+	// MAX_IDLE_CONNS, and HTTPPort becomes HTTP_PORT.
+	NameSnake
+	// NameSkip ignores members without a tag name.
+	NameSkip
+)
+
+// NameFunc returns the env variable name for a struct member without a name in
+// its struct tag. Return "-" to skip the member, or an empty string to flatten
+// it into its parent. The name is upper-cased unless ENV.Low is true.
+type NameFunc func(field reflect.StructField) string
+
+// namer returns the function that names members without a tag name.
+func (e *ENV) namer() NameFunc {
+	switch {
+	case e.NameFunc != nil:
+		return e.NameFunc
+	case e.Naming == NameSkip:
+		return func(reflect.StructField) string { return "-" }
+	case e.Naming == NameSnake:
+		return func(field reflect.StructField) string { return snakeCase(field.Name) }
+	default:
+		return nil // Use the field name.
+	}
+}
+
+// memberName returns the env variable name and the tag options for a struct member.
+// The name is "-" if the member is skipped, and empty if it is flattened into its parent.
+func memberName(member reflect.StructField, tag string, low bool, namer NameFunc) (string, tagOpts) {
+	name, opts := parseTag(member.Tag.Get(tag))
+
+	switch {
+	case name != "" || member.Anonymous:
+	case namer != nil:
+		name = namer(member)
+	default:
+		name = member.Name
+	}
+
+	if !low {
+		name = strings.ToUpper(name) // like "NAME" or "TIMEOUT"
+	}
+
+	return name, opts
+}
+
+// snakeCase puts an underscore between the words of a Go name. It always uses `_`,
+// even when ENV.Sep is NestedSeparator. A word starts with an upper-case letter after
+// a lower-case letter or a digit, or with the last upper-case letter of an acronym
+// that is followed by a lower-case letter: HTTPPort is HTTP_Port.
+func snakeCase(name string) string {
+	var (
+		out   strings.Builder
+		runes = []rune(name)
+	)
+
+	for idx, char := range runes {
+		if idx > 0 && unicode.IsUpper(char) {
+			prev := runes[idx-1]
+			next := idx+1 < len(runes) && unicode.IsLower(runes[idx+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				out.WriteRune('_')
+			}
+		}
+
+		out.WriteRune(char)
+	}
+
+	return out.String()
+}
+
+// specialVar returns the name of a variable that describes a member, like TypeVar or KeysVar.
+func specialVar(tag, sep, name string, low bool) string {
+	if low {
+		return tag + sep + name
+	}
+
+	return tag + sep + strings.ToUpper(name)
+}
@@ -0,0 +1,119 @@
+package cnfg_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type namingDog struct {
+	Name      string
+	Elapsed   cnfg.Duration
+	Owners    []string
+	MaxWeight int
+	Tagged    string `xml:"tag"`
+}
+
+// NamingEmbed is exported so it may be embedded and set.
+type NamingEmbed struct {
+	Embedded string
+}
+
+type namingConfig struct {
+	NamingEmbed
+	Dogs []*namingDog `xml:"dogs"`
+}
+
+func TestNaming(t *testing.T) {
+	t.Parallel()
+
+	config := &namingConfig{
+		NamingEmbed: NamingEmbed{Embedded: "flat"},
+		Dogs: []*namingDog{{
+			Name: "spot", Elapsed: cnfg.Duration{Duration: time.Hour}, Owners: []string{"me"}, MaxWeight: 9, Tagged: "yes",
+		}},
+	}
+
+	for naming, want := range map[cnfg.Naming]cnfg.Pairs{
+		cnfg.NameField: {
+			"APP_EMBEDDED": "flat", "APP_DOGS_0_NAME": "spot", "APP_DOGS_0_ELAPSED": "1h",
+			"APP_DOGS_0_OWNERS_0": "me", "APP_DOGS_0_MAXWEIGHT": "9", "APP_DOGS_0_TAG": "yes",
+		},
+		cnfg.NameSnake: {
+			"APP_EMBEDDED": "flat", "APP_DOGS_0_NAME": "spot", "APP_DOGS_0_ELAPSED": "1h",
+			"APP_DOGS_0_OWNERS_0": "me", "APP_DOGS_0_MAX_WEIGHT": "9", "APP_DOGS_0_TAG": "yes",
+		},
+		cnfg.NameSkip: {"APP_DOGS_0_TAG": "yes"},
+	} {
+		env := &cnfg.ENV{Pfx: "APP", Naming: naming}
+
+		pairs, err := env.Marshal(config)
+		require.NoError(t, err)
+		assert.Equal(t, want, pairs, "naming policy %d", naming)
+
+		parsed := &namingConfig{}
+		_, err = env.UnmarshalMap(pairs, parsed)
+		require.NoError(t, err)
+		assert.Equal(t, "yes", parsed.Dogs[0].Tagged)
+
+		if naming != cnfg.NameSkip {
+			require.NoError(t, env.RoundTrip(config), "untagged members must parse the way they are marshaled")
+		}
+	}
+
+	vars, err := (&cnfg.ENV{Pfx: "APP", Naming: cnfg.NameSnake}).Describe(config)
+	require.NoError(t, err)
+	assert.Equal(t, "APP_DOGS_{n}_MAX_WEIGHT", vars[6].Name)
+}
+
+func TestNameSnake(t *testing.T) {
+	t.Parallel()
+
+	type words struct {
+		MaxIdleConns int
+		HTTPPort     int
+		UserID       int
+		ID           int
+		V2Config     int
+		Simple       int
+	}
+
+	pairs, err := (&cnfg.ENV{Naming: cnfg.NameSnake}).Marshal(&words{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"HTTP_PORT=0", "ID=0", "MAX_IDLE_CONNS=0", "SIMPLE=0", "USER_ID=0", "V2_CONFIG=0",
+	}, pairs.SortedEnv())
+}
+
+func TestNameFunc(t *testing.T) {
+	t.Parallel()
+
+	type jsonOnly struct {
+		Name    string `json:"name"`
+		Timeout int    `json:"timeout_seconds,omitempty"`
+		Skipped string `json:"-"`
+		Other   string
+	}
+
+	env := &cnfg.ENV{Pfx: "APP", NameFunc: func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			return field.Name
+		}
+
+		return name
+	}}
+
+	config := &jsonOnly{Name: "me", Timeout: 5, Skipped: "no", Other: "yes"}
+	pairs, err := env.Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(t, cnfg.Pairs{"APP_NAME": "me", "APP_TIMEOUT_SECONDS": "5", "APP_OTHER": "yes"}, pairs)
+
+	config.Skipped = ""
+	require.NoError(t, env.RoundTrip(config))
+}
@@ -4,8 +4,11 @@ import (
 	"encoding"
 	"errors"
 	"fmt"
+	"maps"
+	"math/big"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,9 +18,173 @@ import (
    using reflection tags from a map of keys and values. */
 
 type parser struct {
-	Low  bool   // allow lowercase variables?
-	Tag  string // struct tag to look for on struct members
-	Vals Pairs  // pairs of env variables (saved at start)
+	Low      bool            // allow lowercase variables?
+	Tag      string          // struct tag to look for on struct members
+	Vals     Pairs           // pairs of env variables (saved at start)
+	Continue bool            // collect errors instead of returning the first
+	Missing  []string        // required variables that were not provided
+	Errs     Errors          // parse errors collected when Continue is true
+	Path     string          // Go path to the current struct member
+	Report   Report          // source of each member's value, only tracked if not nil
+	Defaults bool            // true while parsing struct tag defaults
+	Redact   bool            // redact secret values in errors
+	Secret   bool            // true while parsing a secret member
+	Size     bool            // true while parsing a member with the size option
+	Split    string          // default delimiter for slices and maps in one variable
+	Delim    string          // delimiter for the current member's slices and maps
+	Strict   bool            // return an error for variables that were not used
+	Used     map[string]bool // variables that were parsed into a member, tracked if Strict
+	Accepted map[string]bool // variable names that were looked up, tracked if Strict
+	Types    registry        // concrete types for interface members
+	Namer    NameFunc        // names members without a tag name
+	Sep      string          // level separator
+	Sparse   Sparse          // policy for slice indexes after a missing index
+	Delete   string          // value that deletes a slice item
+}
+
+// Run parses a struct pointer and checks that every required member was provided.
+func (p *parser) Run(field reflect.Value, prefix string) (bool, error) {
+	p.Path = field.Type().Elem().Name()
+	if p.Path == "" {
+		p.Path = field.Type().Elem().String()
+	}
+
+	if p.Strict {
+		p.Used, p.Accepted = make(map[string]bool), make(map[string]bool)
+	}
+
+	exitOk, err := p.Struct(field, prefix)
+	if err != nil {
+		return false, err
+	}
+
+	errs := p.Errs
+	if len(p.Missing) > 0 {
+		errs = append(errs, &MissingError{Vars: p.Missing})
+	}
+
+	if p.Strict {
+		if unknown := p.unknown(prefix); len(unknown) > 0 {
+			errs = append(errs, &UnknownError{Vars: unknown})
+		}
+	}
+
+	switch {
+	case len(errs) == 0:
+		return exitOk, nil
+	case len(errs) == 1 && !p.Continue:
+		return false, errs[0]
+	default:
+		return false, errs
+	}
+}
+
+// lookup returns an env variable, and keeps track of which variables were used.
+func (p *parser) lookup(key string) (string, bool) {
+	val, ok := p.Vals[key]
+	if !p.Strict || p.Defaults {
+		return val, ok // Defaults are not env variables.
+	}
+
+	p.Accepted[key] = true
+	if ok {
+		p.Used[key] = true
+	}
+
+	return val, ok
+}
+
+// lookupMap returns the sorted map keys under a prefix. Values parsed from one
+// variable use the rest of the variable name as the key. Other values use the
+// longest key declared in the KeysVar variable, or the name up to the next separator.
+func (p *parser) lookupMap(typ reflect.Type, prefix string) []string {
+	leaf := p.Types.leaf(typ.Elem())
+	keys := make(map[string]bool)
+	keysVar, list, declared := "", "", false
+
+	if !leaf { // Maps of single values have no KeysVar, so KEYS may be a real key.
+		keysVar = specialVar(prefix, p.Sep, KeysVar, p.Low)
+		list, declared = p.lookup(keysVar)
+	}
+
+	for name := range p.Vals {
+		rest, ok := strings.CutPrefix(name, prefix+p.Sep)
+		if !ok || (declared && name == keysVar) {
+			continue
+		}
+
+		if leaf {
+			keys[rest] = true
+		} else {
+			keys[mapKey(rest, p.Sep, list)] = true
+		}
+	}
+
+	return slices.Sorted(maps.Keys(keys))
+}
+
+// mapKey returns the longest declared key at the start of a name,
+// or the name up to the first separator if no declared key matches.
+func mapKey(name, sep, list string) string {
+	key, _, _ := strings.Cut(name, sep)
+	longest := ""
+
+	for _, declared := range strings.Split(list, KeysSeparator) {
+		if declared != "" && len(declared) > len(longest) &&
+			(name == declared || strings.HasPrefix(name, declared+sep)) {
+			longest = declared
+		}
+	}
+
+	if longest != "" {
+		return longest
+	}
+
+	return key
+}
+
+// parseKey parses a map key from a variable name. The variable is not marked as used,
+// because its value is parsed separately, if it is parsed at all.
+func (p *parser) parseKey(keyval reflect.Value, tag, key string) error {
+	used := p.Used[tag]
+	_, err := p.anything(keyval, tag, key, true, false)
+
+	if p.Strict && !used {
+		delete(p.Used, tag)
+	}
+
+	return err
+}
+
+// collect saves an error and returns nil when the parser is collecting errors.
+// Otherwise, the error is returned and parsing stops.
+func (p *parser) collect(err error) error {
+	if !p.Continue {
+		return err
+	}
+
+	p.Errs = append(p.Errs, err)
+
+	return nil
+}
+
+// enter appends a segment to the Go path and returns a function that restores it.
+// The secret flag is also restored, so it only applies to members of a secret member.
+func (p *parser) enter(segment string) func() {
+	path, secret, size, delim := p.Path, p.Secret, p.Size, p.Delim
+	p.Path += segment
+
+	return func() { p.Path, p.Secret, p.Size, p.Delim = path, secret, size, delim }
+}
+
+// fieldError wraps a parse error with the details of the member it belongs to.
+func (p *parser) fieldError(field reflect.Value, tag, envval string, err error) *FieldError {
+	if p.Secret && p.Redact {
+		err = &redactedError{err: err, value: envval}
+		envval = Redacted
+	}
+
+	return &FieldError{Var: tag, Path: p.Path, Value: envval, Type: field.Type(), Err: err}
 }
 
 // Struct does most of the heavy lifting. Called every time a struct is encountered.
@@ -27,43 +194,84 @@ func (p *parser) Struct(field reflect.Value, prefix string) (bool, error) {
 
 	t := field.Type().Elem()
 	for idx := range t.NumField() { // Loop each struct member
-		tagval := strings.Split(t.Field(idx).Tag.Get(p.Tag), ",")
-		shorttag := tagval[0]
-
-		if !p.Low {
-			shorttag = strings.ToUpper(tagval[0]) // like "NAME" or "TIMEOUT"
-		}
+		member := t.Field(idx)
 
+		shorttag, opts := memberName(member, p.Tag, p.Low, p.Namer)
 		if !field.Elem().Field(idx).CanSet() || shorttag == "-" {
 			continue // This _only_ works with reflection tags.
 		}
 
-		delenv := false
+		tag := strings.Trim(strings.Join([]string{prefix, shorttag}, p.Sep), p.Sep) // PFX_NAME, PFX_TIMEOUT
+		envval, found := p.Vals[tag]                                                // see if it exists
 
-		for i := 1; i < len(tagval); i++ {
-			if tagval[i] == "delenv" {
-				delenv = true
-			}
-		}
-
-		tag := strings.Trim(strings.Join([]string{prefix, shorttag}, LevelSeparator), LevelSeparator) // PFX_NAME, PFX_TIMEOUT
-		envval, found := p.Vals[tag]                                                                  // see if it exists
+		restore := p.enter("." + member.Name)
+		p.Secret = p.Secret || opts.Secret
+		p.Size = opts.Size
+		p.Delim = opts.delim(p.Split)
 
 		//		log.Print("tag ", tag, " = ", envval)
-		exists, err := p.Anything(field.Elem().Field(idx), tag, envval, found, delenv)
+		exists, err := p.Anything(field.Elem().Field(idx), tag, envval, found, opts.Delenv)
 		if err != nil {
-			return false, err
+			if err = p.collect(err); err != nil {
+				return false, err
+			}
+
+			restore()
+
+			continue // The error was saved, and it's not a missing member.
 		} else if exists {
 			exitOk = true
+		} else if opts.Default != "" && field.Elem().Field(idx).IsZero() {
+			// Nothing provided a value, so use the default from the struct tag.
+			if _, err := p.Default(field.Elem().Field(idx), tag, opts.Default); err != nil {
+				if err = p.collect(err); err != nil {
+					return false, err
+				}
+			}
 		}
+
+		if !exists && opts.Required && field.Elem().Field(idx).IsZero() {
+			p.Missing = append(p.Missing, tag) // Keep going to find the rest.
+		}
+
+		restore()
 	}
 
 	return exitOk, nil
 }
 
-//nolint:cyclop
+// Default parses a struct tag default value into a member that has no value.
+// The default is converted into env variable pairs so it takes the same path.
+func (p *parser) Default(field reflect.Value, tag, value string) (bool, error) {
+	vals := p.Vals
+	defer func() { p.Vals = vals }()
+
+	p.Vals = defaultPairs(field.Type(), tag, value, p.Sep)
+	p.Defaults = true
+	p.Report.forget(p.Path) // The default replaces anything recorded so far.
+
+	defer func() { p.Defaults = false }()
+
+	return p.Anything(field, tag, value, true, false)
+}
+
+// Anything parses any type of value, and reports where the value came from.
 func (p *parser) Anything(field reflect.Value, tag, envval string, force, delenv bool) (bool, error) {
+	exists, err := p.anything(field, tag, envval, force, delenv)
+	if err == nil && p.Report != nil {
+		p.record(field, tag, exists)
+	}
+
+	return exists, err
+}
+
+//nolint:cyclop
+func (p *parser) anything(field reflect.Value, tag, envval string, force, delenv bool) (bool, error) {
 	//	log.Println("Anything", envval, tag, field.Kind(), field.Type(), field.Interface())
+	if p.Types.leaf(field.Type()) {
+		_, _ = p.lookup(tag) // Only variables parsed into a single value are used.
+	}
+
 	if exists, err := p.Interface(field, tag, envval, force); err != nil {
 		return false, err
 	} else if exists {
@@ -77,8 +285,16 @@ func (p *parser) Anything(field reflect.Value, tag, envval string, force, delenv
 		return p.Struct(field.Addr(), tag)
 	case reflect.Slice:
 		return p.Slice(field, tag, delenv)
+	case reflect.Array:
+		return p.Array(field, tag, delenv)
 	case reflect.Map:
 		return p.Map(field, tag, delenv)
+	case reflect.Interface:
+		if p.Types[field.Type()] != nil {
+			return p.Concrete(field, tag, envval, force, delenv)
+		}
+
+		fallthrough
 	default:
 		if delenv {
 			_ = os.Unsetenv(tag) // delete it if it was requested in the env tag.
@@ -99,11 +315,17 @@ func (p *parser) Pointer(field reflect.Value, tag, envval string, delenv bool) (
 		value = field.Elem().Addr()
 	}
 
+	missing := len(p.Missing)
+
 	// Pass the non-pointer element back into the start.
 	found, err := p.Anything(value.Elem(), tag, envval, false, delenv)
 	if found {
 		// overwrite the pointer only if something was parsed.
 		field.Set(value)
+	} else if err == nil && field.IsNil() {
+		// This value is not created, so its required members are not missing.
+		p.Missing = p.Missing[:missing]
+		p.Report.forget(p.Path)
 	}
 
 	return found, err
@@ -121,7 +343,7 @@ func (p *parser) Interface(field reflect.Value, tag, envval string, force bool)
 
 	if v, ok := field.Addr().Interface().(ENVUnmarshaler); ok {
 		if err := v.UnmarshalENV(tag, envval); err != nil {
-			return false, fmt.Errorf("UnmarshalENV interface: %w", err)
+			return false, p.fieldError(field, tag, envval, fmt.Errorf("UnmarshalENV interface: %w", err))
 		}
 
 		return true, nil
@@ -131,9 +353,14 @@ func (p *parser) Interface(field reflect.Value, tag, envval string, force bool)
 		return false, nil
 	}
 
+	// big.Float rounds to 64 bits if it has no precision, so give it enough for every digit.
+	if v, ok := field.Addr().Interface().(*big.Float); ok && v.Prec() < floatPrec(envval) {
+		v.SetPrec(floatPrec(envval))
+	}
+
 	if v, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
 		if err := v.UnmarshalText([]byte(envval)); err != nil {
-			return false, fmt.Errorf("UnmarshalText interface: %w", err)
+			return false, p.fieldError(field, tag, envval, fmt.Errorf("UnmarshalText interface: %w", err))
 		}
 
 		return true, nil
@@ -144,7 +371,7 @@ func (p *parser) Interface(field reflect.Value, tag, envval string, force bool)
 	// _after_ TextUnmarshaler fixed the time.Time bug, so it's "ok"
 	if v, ok := field.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
 		if err := v.UnmarshalBinary([]byte(envval)); err != nil {
-			return false, fmt.Errorf("UnmarshalBinary interface: %w", err)
+			return false, p.fieldError(field, tag, envval, fmt.Errorf("UnmarshalBinary interface: %w", err))
 		}
 
 		return true, nil
@@ -164,6 +391,14 @@ func (p *parser) Member(field reflect.Value, tag, envval string, force bool) (bo
 		return true, nil
 	}
 
+	if p.Size && isInteger(field.Kind()) {
+		if err := parseSize(field, envval); err != nil {
+			return false, p.fieldError(field, tag, envval, err)
+		}
+
+		return true, nil
+	}
+
 	switch fieldType := field.Interface().(type) {
 	// Handle each member type appropriately (differently).
 	case string:
@@ -186,6 +421,16 @@ func (p *parser) Member(field reflect.Value, tag, envval string, force bool) (bo
 
 		val, err = strconv.ParseFloat(envval, bits32)
 		field.SetFloat(val)
+	case complex128:
+		var val complex128
+
+		val, err = strconv.ParseComplex(envval, bits128)
+		field.SetComplex(val)
+	case complex64:
+		var val complex128
+
+		val, err = strconv.ParseComplex(envval, bits64)
+		field.SetComplex(val)
 	case time.Duration:
 		var val time.Duration
 
@@ -201,7 +446,7 @@ func (p *parser) Member(field reflect.Value, tag, envval string, force bool) (bo
 	}
 
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", tag, err)
+		return false, p.fieldError(field, tag, envval, err)
 	}
 
 	return true, nil
@@ -220,11 +465,15 @@ func (p *parser) customMember(field reflect.Value, tag, envval string, force boo
 		// SetString is a reflect package method to update a struct member by index.
 		field.SetString(envval)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		err = parseUint(field, field.Interface(), envval)
+		var val uint64
+
+		// Named types, like `type Port uint16`, are sized by their kind.
+		val, err = strconv.ParseUint(envval, baseAuto, field.Type().Bits())
+		field.SetUint(val)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		var val int64
 
-		val, err = parseInt(field.Interface(), envval)
+		val, err = strconv.ParseInt(envval, baseAuto, field.Type().Bits())
 		field.SetInt(val)
 	case reflect.Float64:
 		var val float64
@@ -236,17 +485,28 @@ func (p *parser) customMember(field reflect.Value, tag, envval string, force boo
 
 		val, err = strconv.ParseFloat(envval, bits32)
 		field.SetFloat(val)
+	case reflect.Complex128:
+		var val complex128
+
+		val, err = strconv.ParseComplex(envval, bits128)
+		field.SetComplex(val)
+	case reflect.Complex64:
+		var val complex128
+
+		val, err = strconv.ParseComplex(envval, bits64)
+		field.SetComplex(val)
 	case reflect.Bool:
 		var val bool
 
 		val, err = strconv.ParseBool(envval)
 		field.SetBool(val)
 	default:
-		return false, fmt.Errorf("%w: type: %T, kind: %v, value: %s", ErrUnsupported, field.Interface(), field.Kind(), envval)
+		return false, p.fieldError(field, tag, envval,
+			fmt.Errorf("%w: type: %T, kind: %v, value: %s", ErrUnsupported, field.Interface(), field.Kind(), envval))
 	}
 
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", tag, err)
+		return false, p.fieldError(field, tag, envval, err)
 	}
 
 	return true, nil
@@ -263,12 +523,15 @@ func (p *parser) Slice(field reflect.Value, tag string, delenv bool) (bool, erro
 
 	// slice of bytes works differently than any other slice type.
 	if value.Type().String() == "[]uint8" {
-		envval, exists := p.Vals[tag]
+		envval, exists := p.lookup(tag)
 		found = exists
 
 		value.SetBytes([]byte(envval))
-	} else {
-		found, err = p.SliceValue(value, tag, delenv)
+	} else if found, err = p.splitSlice(value, tag); err == nil {
+		var indexed bool
+
+		indexed, err = p.SliceValue(value, tag, delenv)
+		found = found || indexed
 	}
 
 	if delenv {
@@ -282,18 +545,127 @@ func (p *parser) Slice(field reflect.Value, tag string, delenv bool) (bool, erro
 	return found, err
 }
 
-func (p *parser) SliceValue(field reflect.Value, tag string, delenv bool) (bool, error) {
+// Array parses fixed-size arrays. Byte arrays are parsed from a single variable,
+// like a []byte. Other arrays use one variable per index, like slices do.
+func (p *parser) Array(field reflect.Value, tag string, delenv bool) (bool, error) {
+	if field.Type().Elem().Kind() == reflect.Uint8 {
+		envval, exists := p.lookup(tag)
+		if delenv {
+			_ = os.Unsetenv(tag) // delete it if it was requested in the env tag.
+		}
+
+		if !exists {
+			return false, nil
+		}
+
+		if len(envval) > field.Len() {
+			return false, p.fieldError(field, tag, envval,
+				fmt.Errorf("%w: %d bytes provided, array holds %d", ErrArrayBounds, len(envval), field.Len()))
+		}
+
+		for idx := range field.Len() {
+			field.Index(idx).SetUint(0)
+
+			if idx < len(envval) {
+				field.Index(idx).SetUint(uint64(envval[idx]))
+			}
+		}
+
+		return true, nil
+	}
+
+	if err := p.arrayBounds(field, tag); err != nil {
+		return false, err
+	}
+
 	var found bool
 
+	for idx := range field.Len() {
+		ntag := strings.Join([]string{tag, strconv.Itoa(idx)}, p.Sep)
+		envval, exists := p.Vals[ntag] // Marked as used where it's parsed.
+
+		if delenv {
+			_ = os.Unsetenv(ntag) // delete it if it was requested in the env tag.
+		}
+
+		restore := p.enter("[" + strconv.Itoa(idx) + "]")
+		exists, err := p.Anything(field.Index(idx), ntag, envval, exists, delenv)
+
+		restore()
+
+		if err != nil {
+			if err = p.collect(err); err != nil {
+				return false, err
+			}
+		} else if exists {
+			found = true
+		}
+	}
+
+	return found, nil
+}
+
+// arrayBounds returns an error if a variable has an index that does not fit in the array.
+func (p *parser) arrayBounds(field reflect.Value, tag string) error {
+	for _, key := range p.Vals.SortedKeys() {
+		if !strings.HasPrefix(key, tag+p.Sep) {
+			continue
+		}
+
+		index, _, _ := strings.Cut(strings.TrimPrefix(key, tag+p.Sep), p.Sep)
+		if idx, err := strconv.Atoi(index); err == nil && (idx < 0 || idx >= field.Len()) {
+			return p.fieldError(field, key, p.Vals[key],
+				fmt.Errorf("%w: index %d, array length is %d", ErrArrayBounds, idx, field.Len()))
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) SliceValue(field reflect.Value, tag string, delenv bool) (bool, error) { //nolint:cyclop,funlen
+	found, err := p.sliceLen(field, tag, delenv)
+	if err != nil {
+		return false, err
+	}
+
+	last, lastVar := p.lastIndex(tag)
+	fill := -1 // The last index to parse, even if the indexes before it are missing.
+
+	if p.Sparse == SparseFill {
+		fill = last
+	}
+
+	if fill >= max(field.Len(), MaxSliceLen) {
+		return false, p.fieldError(field, lastVar, p.Vals[lastVar],
+			fmt.Errorf("%w: index %d, maximum length is %d", ErrSliceLen, fill, MaxSliceLen))
+	}
+
+	deleted := []int{}
+
 	total := field.Len()
-	for idx := 0; idx <= total; idx++ {
-		ntag := strings.Join([]string{tag, strconv.Itoa(idx)}, LevelSeparator)
-		envval, exists := p.Vals[ntag]
+	for idx := 0; idx <= max(total, fill); idx++ {
+		ntag := strings.Join([]string{tag, strconv.Itoa(idx)}, p.Sep)
+		envval, exists := p.Vals[ntag] // Marked as used where it's parsed.
+		missing := len(p.Missing)
 
 		if delenv {
 			_ = os.Unsetenv(ntag) // delete it if it was requested in the env tag.
 		}
 
+		if exists && p.Delete != "" && envval == p.Delete {
+			_, _ = p.lookup(ntag) // The variable deleted the item, so it's used.
+			found = true
+			deleted = append(deleted, idx)
+
+			if idx >= field.Len() {
+				// Keep the position, so the next indexes line up. It's removed below.
+				total++
+				field.Set(reflect.Append(field, reflect.Zero(field.Type().Elem())))
+			}
+
+			continue
+		}
+
 		// Start with a blank value for this item
 		value := reflect.Indirect(reflect.New(field.Type().Elem()))
 		if idx < field.Len() {
@@ -301,9 +673,24 @@ func (p *parser) SliceValue(field reflect.Value, tag string, delenv bool) (bool,
 			value = reflect.Indirect(field.Index(idx).Addr())
 		}
 
-		if exists, err := p.Anything(value, ntag, envval, exists, delenv); err != nil {
-			return false, err
-		} else if !exists {
+		restore := p.enter("[" + strconv.Itoa(idx) + "]")
+		exists, err := p.Anything(value, ntag, envval, exists, delenv)
+
+		restore()
+
+		if err != nil {
+			if err = p.collect(err); err != nil {
+				return false, err
+			}
+
+			continue
+		} else if !exists && (idx < field.Len() || idx > fill) {
+			if idx >= field.Len() {
+				// This item does not exist, so its required members are not missing.
+				p.Missing = p.Missing[:missing]
+				p.Report.forget(p.Path + "[" + strconv.Itoa(idx) + "]")
+			}
+
 			continue
 		}
 
@@ -322,54 +709,89 @@ func (p *parser) SliceValue(field reflect.Value, tag string, delenv bool) (bool,
 		field.Index(idx).Set(value)
 	}
 
+	if p.Sparse == SparseError && last >= field.Len() {
+		return false, p.fieldError(field, lastVar, p.Vals[lastVar],
+			fmt.Errorf("%w: index %d, slice length is %d", ErrSliceIndex, last, field.Len()))
+	}
+
+	if len(deleted) > 0 {
+		field.Set(deleteItems(field, deleted))
+	}
+
 	return found, nil
 }
 
 func (p *parser) Map(field reflect.Value, tag string, delenv bool) (bool, error) {
-	var found bool
+	found, err := p.splitMap(field, tag)
+	if err != nil {
+		return false, err
+	}
 
-	vals := p.Vals.Get(tag) // key=val, ... (prefix stripped)
-	if len(vals) < 1 {
-		return false, nil
+	keys := p.lookupMap(field.Type(), tag) // prefix stripped
+	if len(keys) < 1 {
+		return found, nil
 	}
 
 	if field.IsNil() {
 		field.Set(reflect.MakeMap(field.Type()))
 	}
 
-	for key, val := range vals {
+	for _, key := range keys {
+		ntag := strings.Join([]string{tag, key}, p.Sep)
+		val, exists := p.Vals[ntag] // Marked as used where it's parsed.
+
 		if delenv {
-			_ = os.Unsetenv(key)
+			_ = os.Unsetenv(ntag)
 		}
 
+		restore := p.enter("[" + key + "]")
+
 		// Maps have 2 types. The index and the value. First, parse the index into its type.
 		keyval := reflect.Indirect(reflect.New(field.Type().Key()))
-		if _, err := p.Anything(keyval, tag, key, true, delenv); err != nil {
-			return false, err
+		if err := p.parseKey(keyval, ntag, key); err != nil {
+			restore()
+
+			if err = p.collect(err); err != nil {
+				return false, err
+			}
+
+			continue
 		}
 
-		if val == "" {
-			// a blank env value was provided, set the field to nil.
+		if exists && val == "" {
+			// a blank env value was provided, delete the entry. This works for every value type.
+			_, _ = p.lookup(ntag)
 			found = true
 
 			field.SetMapIndex(keyval, reflect.Value{})
+			restore()
 
 			continue
 		}
 
-		// And now parse the second type: the value.
+		// And now parse the second type: the value. Start with the existing value, like struct members.
 		valval := reflect.Indirect(reflect.New(field.Type().Elem()))
+		if existing := field.MapIndex(keyval); existing.IsValid() {
+			valval.Set(existing)
+		}
+
+		exists, err := p.Anything(valval, ntag, val, exists, delenv)
+
+		restore()
 
-		exists, err := p.Anything(valval, strings.Join([]string{tag, key}, LevelSeparator), val, true, delenv)
 		if err != nil {
-			return false, err
+			if err = p.collect(err); err != nil {
+				return false, err
+			}
+
+			continue
 		}
 
 		if exists {
 			found = true
-		}
 
-		field.SetMapIndex(keyval, valval)
+			field.SetMapIndex(keyval, valval)
+		}
 	}
 
 	return found, nil
@@ -384,7 +806,7 @@ func parseUint(field reflect.Value, intType any, envval string) error {
 
 	switch intType.(type) {
 	default:
-		val, err = strconv.ParseUint(envval, base10, 0)
+		val, err = strconv.ParseUint(envval, baseAuto, 0)
 	case uint8:
 		// this crap is to support byte and []byte
 		switch len(envval) {
@@ -400,11 +822,11 @@ func parseUint(field reflect.Value, intType any, envval string) error {
 			return fmt.Errorf("%w: %s", ErrInvalidByte, envval)
 		}
 	case uint16:
-		val, err = strconv.ParseUint(envval, base10, bits16)
+		val, err = strconv.ParseUint(envval, baseAuto, bits16)
 	case uint32:
-		val, err = strconv.ParseUint(envval, base10, bits32)
+		val, err = strconv.ParseUint(envval, baseAuto, bits32)
 	case uint64:
-		val, err = strconv.ParseUint(envval, base10, bits64)
+		val, err = strconv.ParseUint(envval, baseAuto, bits64)
 	}
 
 	if err != nil {
@@ -425,15 +847,15 @@ func parseInt(intType any, envval string) (int64, error) {
 
 	switch intType.(type) {
 	default:
-		out, err = strconv.ParseInt(envval, base10, 0)
+		out, err = strconv.ParseInt(envval, baseAuto, 0)
 	case int8:
-		out, err = strconv.ParseInt(envval, base10, bits8)
+		out, err = strconv.ParseInt(envval, baseAuto, bits8)
 	case int16:
-		out, err = strconv.ParseInt(envval, base10, bits16)
+		out, err = strconv.ParseInt(envval, baseAuto, bits16)
 	case int32:
-		out, err = strconv.ParseInt(envval, base10, bits32)
+		out, err = strconv.ParseInt(envval, baseAuto, bits32)
 	case int64:
-		out, err = strconv.ParseInt(envval, base10, bits64)
+		out, err = strconv.ParseInt(envval, baseAuto, bits64)
 	}
 
 	if err != nil {
@@ -442,3 +864,10 @@ func parseInt(intType any, envval string) (int64, error) {
 
 	return out, nil
 }
+
+// floatPrec returns a big.Float precision, in bits, that holds every digit in a value.
+func floatPrec(envval string) uint {
+	const bitsPerDigit = 4 // 4 bits holds a decimal or hexadecimal digit.
+
+	return max(bits64, uint(len(envval))*bitsPerDigit)
+}
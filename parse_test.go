@@ -20,6 +20,13 @@ func TestParseInt(t *testing.T) {
 		require.NoError(t, err)
 		assert.EqualValues(val, i)
 	}
+
+	for envval, want := range map[string]int64{"0755": 0o755, "0o17": 0o17, "0xff": 0xff, "-0b101": -5, "1_000": 1000} {
+		i, err := parseInt(int64(0), envval)
+
+		require.NoError(t, err, envval)
+		assert.Equal(want, i, "base prefixes and underscores must be accepted: %s", envval)
+	}
 }
 
 func TestParseByteSlice(t *testing.T) {
@@ -57,6 +64,10 @@ func TestParseUint(t *testing.T) {
 		assert.EqualValues(1, embeddedInt.F)
 	}
 
+	require.NoError(t, parseUint(theField, uint64(0), "0xFF_FF"))
+	assert.EqualValues(0xffff, embeddedInt.F)
+	require.Error(t, parseUint(theField, uint16(0), "0x1_0000"), "must not overflow")
+
 	type test2 struct {
 		F byte
 	}
@@ -88,3 +99,58 @@ func TestParseInterfaceError(t *testing.T) {
 	require.NoError(t, err, "unaddressable value must return nil")
 	assert.False(ok, "unaddressable value must return false")
 }
+
+func TestParseArray(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	type point struct {
+		X int `xml:"x"`
+		Y int `xml:"y"`
+	}
+
+	type arrays struct {
+		Names  [4]string `xml:"name"`
+		Hash   [8]byte   `xml:"hash"`
+		Points [3]point  `xml:"point"`
+		Ptr    *[2]int   `xml:"ptr"`
+		Def    [3]int    `xml:"def,default=1;2;3"`
+	}
+
+	config := &arrays{Names: [4]string{"a", "b", "c", "d"}, Hash: [8]byte{9, 9, 9, 9, 9, 9, 9, 9}}
+	pairs := Pairs{
+		"A_NAME_1":    "bee",
+		"A_NAME_3":    "dee",
+		"A_HASH":      "abc",
+		"A_POINT_2_X": "5",
+		"A_POINT_0_Y": "-1",
+		"A_PTR_1":     "7",
+	}
+
+	ok, err := (&ENV{Pfx: "A"}).UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.True(ok)
+	assert.Equal([4]string{"a", "bee", "c", "dee"}, config.Names)
+	assert.Equal([8]byte{'a', 'b', 'c'}, config.Hash, "the rest of a byte array must be zeroed")
+	assert.Equal([3]point{{Y: -1}, {}, {X: 5}}, config.Points)
+	require.NotNil(t, config.Ptr)
+	assert.Equal([2]int{0, 7}, *config.Ptr)
+	assert.Equal([3]int{1, 2, 3}, config.Def)
+
+	out, err := (&ENV{Pfx: "A"}).Marshal(config)
+	require.NoError(t, err)
+	assert.Equal("abc", out["A_HASH"])
+	assert.Equal("dee", out["A_NAME_3"])
+	assert.Equal("5", out["A_POINT_2_X"])
+	assert.Equal("7", out["A_PTR_1"])
+
+	_, err = (&ENV{Pfx: "A"}).UnmarshalMap(Pairs{"A_NAME_4": "out of range"}, config)
+	require.ErrorIs(t, err, ErrArrayBounds)
+
+	_, err = (&ENV{Pfx: "A"}).UnmarshalMap(Pairs{"A_POINT_3_X": "1"}, config)
+	require.ErrorIs(t, err, ErrArrayBounds)
+
+	_, err = (&ENV{Pfx: "A"}).UnmarshalMap(Pairs{"A_HASH": "more than eight"}, config)
+	require.ErrorIs(t, err, ErrArrayBounds)
+}
@@ -0,0 +1,90 @@
+package cnfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+/* This file contains the logic to quote Pairs for shells and other tools. */
+
+// QuoteStyle selects how Pairs.QuotedStyle quotes values.
+type QuoteStyle int
+
+// These are the supported quoting styles.
+const (
+	// QuotePOSIX wraps values in single quotes: KEY='value'. Single quotes are
+	// written as '\''. This is safe for any POSIX shell, including sh and bash.
+	QuotePOSIX QuoteStyle = iota
+	// QuoteBash uses ANSI-C quoting: KEY=$'value'. Control characters, like
+	// newlines, are escaped so every pair stays on one line. Works in bash and zsh.
+	QuoteBash
+	// QuoteDocker writes KEY=value for a docker --env-file. Docker does not
+	// process quotes or escapes, so values are literal and cannot contain newlines.
+	QuoteDocker
+	// QuoteSystemd writes Environment="KEY=value" for a systemd unit file.
+	// Quotes, backslashes and control characters are escaped, and % is written as %%.
+	QuoteSystemd
+)
+
+// Quoted turns the Pairs map into an environment variable slice that can be used
+// by sh, bash or other POSIX shells. Values are single quoted, so $, backticks,
+// double quotes and newlines are never interpreted by the shell. Sorted by name.
+func (p Pairs) Quoted() []string {
+	env, _ := p.QuotedStyle(QuotePOSIX) // POSIX quoting never returns an error.
+
+	return env
+}
+
+// QuotedStyle turns the Pairs map into an environment variable slice using a
+// specific quoting style, sorted by name. An error is returned if a value cannot be quoted.
+func (p Pairs) QuotedStyle(style QuoteStyle) ([]string, error) {
+	env := make([]string, 0, len(p))
+
+	for _, key := range p.SortedKeys() {
+		val := p[key]
+
+		switch style {
+		case QuotePOSIX:
+			env = append(env, key+"='"+strings.ReplaceAll(val, "'", `'\''`)+"'")
+		case QuoteBash:
+			env = append(env, key+"=$'"+escapeC(val, "'")+"'")
+		case QuoteDocker:
+			if strings.ContainsAny(val, "\r\n") {
+				return nil, fmt.Errorf("%w: %s: docker env files cannot contain newlines", ErrUnquotable, key)
+			}
+
+			env = append(env, key+"="+val)
+		case QuoteSystemd:
+			env = append(env, `Environment="`+strings.ReplaceAll(escapeC(key+"="+val, `"`), "%", "%%")+`"`)
+		default:
+			return nil, fmt.Errorf("%w: unknown style %d", ErrUnquotable, style)
+		}
+	}
+
+	return env, nil
+}
+
+// escapeC escapes a string with C-style backslash escapes.
+// Backslashes, the provided quote and control characters are escaped.
+func escapeC(val, quote string) string {
+	var buf strings.Builder
+
+	for _, char := range val {
+		switch {
+		case char == '\\' || string(char) == quote:
+			buf.WriteString(`\` + string(char))
+		case char == '\n':
+			buf.WriteString(`\n`)
+		case char == '\r':
+			buf.WriteString(`\r`)
+		case char == '\t':
+			buf.WriteString(`\t`)
+		case char < ' ' || char == 0x7f:
+			fmt.Fprintf(&buf, `\x%02x`, char)
+		default:
+			buf.WriteRune(char)
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,69 @@
+package cnfg_test
+
+import (
+	"os/exec"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+func TestQuotedStyle(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	pairs := cnfg.Pairs{"KEY": "a=b 'c' \"d\" $e `f` \\g\nh\t%i\x01"}
+
+	for style, expect := range map[cnfg.QuoteStyle]string{
+		cnfg.QuotePOSIX:   `KEY='a=b '\''c'\'' "d" $e ` + "`f`" + ` \g` + "\n" + `h` + "\t" + `%i` + "\x01'",
+		cnfg.QuoteBash:    `KEY=$'a=b \'c\' "d" $e ` + "`f`" + ` \\g\nh\t%i\x01'`,
+		cnfg.QuoteSystemd: `Environment="KEY=a=b 'c' \"d\" $e ` + "`f`" + ` \\g\nh\t%%i\x01"`,
+	} {
+		env, err := pairs.QuotedStyle(style)
+		require.NoError(t, err)
+		assert.Equal([]string{expect}, env)
+	}
+
+	_, err := pairs.QuotedStyle(cnfg.QuoteDocker)
+	require.ErrorIs(t, err, cnfg.ErrUnquotable, "docker env files cannot contain newlines")
+
+	env, err := cnfg.Pairs{"KEY": `a="b" $c`}.QuotedStyle(cnfg.QuoteDocker)
+	require.NoError(t, err)
+	assert.Equal([]string{`KEY=a="b" $c`}, env)
+
+	_, err = pairs.QuotedStyle(cnfg.QuoteStyle(99))
+	require.ErrorIs(t, err, cnfg.ErrUnquotable)
+}
+
+// TestQuotedShell makes sure the quoted values survive a trip through a real shell.
+func TestQuotedShell(t *testing.T) {
+	t.Parallel()
+
+	pairs := cnfg.Pairs{
+		"APP_PASS":  `pa$$word"; echo pwned; '` + "`id`",
+		"APP_EQUAL": "a=b=c",
+		"APP_MULTI": "line one\nline two",
+	}
+
+	for style, shell := range map[cnfg.QuoteStyle]string{cnfg.QuotePOSIX: "sh", cnfg.QuoteBash: "bash"} {
+		path, err := exec.LookPath(shell)
+		if err != nil {
+			t.Logf("skipping %s: %v", shell, err)
+			continue
+		}
+
+		env, err := pairs.QuotedStyle(style)
+		require.NoError(t, err)
+		sort.Strings(env)
+
+		script := strings.Join(env, "\n") + "\n" +
+			`printf '%s\0' "$APP_EQUAL" "$APP_MULTI" "$APP_PASS"`
+
+		out, err := exec.Command(path, "-c", script).Output() //nolint:gosec // this is a test.
+		require.NoError(t, err)
+		assert.Equal(t, pairs["APP_EQUAL"]+"\x00"+pairs["APP_MULTI"]+"\x00"+pairs["APP_PASS"]+"\x00", string(out), shell)
+	}
+}
@@ -0,0 +1,158 @@
+package cnfg
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+)
+
+/* This file contains the logic to parse and marshal interface-typed struct members. */
+
+// TypeVar is appended to an interface member's variable name to select the
+// concrete type to parse into. ie. APP_BACKEND_TYPE=s3 selects the type
+// registered as "s3", and the rest of the APP_BACKEND_* variables are parsed
+// into it. This is upper-cased unless ENV.Low is true.
+const TypeVar = "type"
+
+// registry maps interface types to the concrete types registered for them, by name.
+type registry map[reflect.Type]map[string]reflect.Type
+
+// Register adds a concrete type that may be parsed into interface-typed struct
+// members. Pass a nil pointer to the interface, the name used in the TypeVar
+// variable, and a value of the concrete type. The concrete type may be a pointer.
+//
+//	err := env.Register((*Storage)(nil), "s3", &S3Storage{})
+//
+// Marshal writes the name back out in the TypeVar variable.
+func (e *ENV) Register(iface any, name string, concrete any) error {
+	ifaceType := reflect.TypeOf(iface)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("%w: %T is not a pointer to an interface", ErrRegister, iface)
+	}
+
+	ifaceType = ifaceType.Elem()
+
+	concreteType := reflect.TypeOf(concrete)
+	if concreteType == nil || !concreteType.Implements(ifaceType) {
+		return fmt.Errorf("%w: %T does not implement %v", ErrRegister, concrete, ifaceType)
+	}
+
+	if e.types == nil {
+		e.types = make(registry)
+	}
+
+	if e.types[ifaceType] == nil {
+		e.types[ifaceType] = make(map[string]reflect.Type)
+	}
+
+	e.types[ifaceType][name] = concreteType
+
+	return nil
+}
+
+// leaf returns true if a type is parsed from a single variable. This is isLeaf,
+// with pointers dereferenced, and false for interfaces with registered types.
+func (r registry) leaf(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return isLeaf(typ) && r[typ] == nil
+}
+
+// name returns the registered name for a concrete type.
+func (r registry) name(iface, concrete reflect.Type) (string, bool) {
+	for name, typ := range r[iface] {
+		if typ == concrete {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// Concrete parses an interface member into the registered type selected by the
+// TypeVar variable. If that variable is not set, an existing value is updated.
+// The member's own variable is passed through for types parsed from one variable.
+func (p *parser) Concrete(field reflect.Value, tag, envval string, force, delenv bool) (bool, error) {
+	ttag := specialVar(tag, p.Sep, TypeVar, p.Low)
+	name, exists := p.lookup(ttag)
+
+	if delenv {
+		_ = os.Unsetenv(ttag) // delete it if it was requested in the env tag.
+	}
+
+	value := reflect.Value{}
+
+	switch typ, ok := p.Types[field.Type()][name]; {
+	case exists && !ok:
+		return false, p.fieldError(field, ttag, name, fmt.Errorf("%w: %s", ErrUnknownType, name))
+	case exists && (field.IsNil() || field.Elem().Type() != typ):
+		value = reflect.New(typ).Elem() // A new value of the selected type.
+		if typ.Kind() == reflect.Ptr {
+			value.Set(reflect.New(typ.Elem()))
+		}
+	case field.IsNil():
+		return false, nil // Nothing to parse into.
+	default:
+		value = reflect.New(field.Elem().Type()).Elem() // A copy of the existing value.
+		value.Set(field.Elem())
+	}
+
+	found, err := p.Anything(value, tag, envval, force, delenv)
+	if err != nil {
+		return false, err
+	}
+
+	if found || exists {
+		field.Set(value)
+	}
+
+	return found || exists, nil
+}
+
+// Concrete marshals an interface member holding a registered type. The registered
+// name is written to the TypeVar variable.
+func (p *unparser) Concrete(field reflect.Value, tag string, omitempty bool) (Pairs, bool, error) {
+	if field.IsNil() {
+		return Pairs{}, false, nil
+	}
+
+	name, ok := p.Types.name(field.Type(), field.Elem().Type())
+	if !ok {
+		return Pairs{}, false, nil
+	}
+
+	value := reflect.New(field.Elem().Type()).Elem()
+	value.Set(field.Elem())
+
+	ttag := specialVar(tag, p.Sep, TypeVar, p.Low)
+	p.Keys = append(p.Keys, ttag)
+
+	output, err := p.Anything(value, tag, omitempty)
+	if err != nil {
+		return nil, true, err
+	}
+
+	output.Set(ttag, name)
+
+	return output, true, nil
+}
+
+// Concrete describes the TypeVar variable for an interface member, and the
+// variables of every type registered for it.
+func (d *describer) Concrete(typ reflect.Type, tag, path string, info VarInfo) {
+	names := slices.Sorted(maps.Keys(d.Types[typ]))
+
+	selector := info
+	selector.Name, selector.Path, selector.Type = specialVar(tag, d.Sep, TypeVar, d.Low), path+".(type)", "string"
+	selector.Values = names
+	d.Vars = append(d.Vars, selector)
+
+	for _, name := range names {
+		concrete := d.Types[typ][name]
+		d.Anything(concrete, tag, path+".("+concrete.String()+")", info)
+	}
+}
@@ -0,0 +1,171 @@
+package cnfg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type storage interface {
+	Store() string
+}
+
+type s3Storage struct {
+	Bucket string `xml:"bucket"`
+	Region string `xml:"region,default=us-east-1"`
+}
+
+func (s *s3Storage) Store() string { return "s3://" + s.Bucket }
+
+type fileStorage struct {
+	Path string `xml:"path"`
+}
+
+func (f fileStorage) Store() string { return "file://" + f.Path }
+
+type modeStorage string
+
+func (m modeStorage) Store() string { return "mode://" + string(m) }
+
+type storageConfig struct {
+	Name    string             `xml:"name"`
+	Backend storage            `xml:"backend"`
+	Mirrors map[string]storage `xml:"mirror"`
+}
+
+func storageENV(t *testing.T) *cnfg.ENV {
+	t.Helper()
+
+	env := &cnfg.ENV{Pfx: "APP"}
+	require.NoError(t, env.Register((*storage)(nil), "s3", &s3Storage{}))
+	require.NoError(t, env.Register((*storage)(nil), "file", fileStorage{}))
+
+	return env
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	env := &cnfg.ENV{}
+	require.ErrorIs(t, env.Register(storage(nil), "s3", &s3Storage{}), cnfg.ErrRegister, "must be a pointer")
+	require.ErrorIs(t, env.Register((*storage)(nil), "s3", s3Storage{}), cnfg.ErrRegister, "must implement")
+	require.NoError(t, env.Register((*storage)(nil), "s3", &s3Storage{}))
+}
+
+func TestUnmarshalRegistered(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := storageENV(t)
+	pairs := map[string]string{
+		"APP_BACKEND_TYPE":         "s3",
+		"APP_BACKEND_BUCKET":       "files",
+		"APP_MIRROR_local_TYPE":    "file",
+		"APP_MIRROR_local_PATH":    "/tmp",
+		"APP_MIRROR_backup_TYPE":   "s3",
+		"APP_MIRROR_backup_REGION": "eu-west-1",
+	}
+	config := &storageConfig{}
+
+	worked, err := env.UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.True(worked)
+	assert.Equal(&s3Storage{Bucket: "files", Region: "us-east-1"}, config.Backend)
+	assert.Equal(fileStorage{Path: "/tmp"}, config.Mirrors["local"])
+	assert.Equal(&s3Storage{Region: "eu-west-1"}, config.Mirrors["backup"])
+
+	// Without a type variable the existing value is updated.
+	worked, err = env.UnmarshalMap(map[string]string{"APP_BACKEND_BUCKET": "other"}, config)
+	require.NoError(t, err)
+	assert.True(worked)
+	assert.Equal("s3://other", config.Backend.Store())
+
+	_, err = env.UnmarshalMap(map[string]string{"APP_BACKEND_TYPE": "gcs"}, config)
+	require.ErrorIs(t, err, cnfg.ErrUnknownType)
+
+	var fieldErr *cnfg.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal("APP_BACKEND_TYPE", fieldErr.Var)
+
+	config = &storageConfig{}
+	worked, err = env.UnmarshalMap(map[string]string{"APP_NAME": "me"}, config)
+	require.NoError(t, err)
+	assert.True(worked)
+	assert.Nil(config.Backend, "an interface without a type variable must stay nil")
+
+	_, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, &storageConfig{})
+	require.ErrorIs(t, err, cnfg.ErrUnsupported, "interfaces without registered types are not supported")
+}
+
+func TestMarshalRegistered(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := storageENV(t)
+	config := &storageConfig{
+		Backend: &s3Storage{Bucket: "files", Region: "us-east-1"},
+		Mirrors: map[string]storage{"local": fileStorage{Path: "/tmp"}},
+	}
+
+	pairs, keys, err := env.MarshalOrder(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{
+		"APP_NAME":              "",
+		"APP_BACKEND_TYPE":      "s3",
+		"APP_BACKEND_BUCKET":    "files",
+		"APP_BACKEND_REGION":    "us-east-1",
+		"APP_MIRROR_local_TYPE": "file",
+		"APP_MIRROR_local_PATH": "/tmp",
+	}, pairs)
+	assert.Equal([]string{
+		"APP_NAME", "APP_BACKEND_TYPE", "APP_BACKEND_BUCKET", "APP_BACKEND_REGION",
+		"APP_MIRROR_local_TYPE", "APP_MIRROR_local_PATH",
+	}, keys)
+
+	parsed := &storageConfig{}
+	_, err = env.UnmarshalMap(pairs, parsed)
+	require.NoError(t, err)
+	assert.Equal(config, parsed, "marshaled output must parse back into the same types")
+}
+
+func TestRegisteredValue(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := &cnfg.ENV{Pfx: "APP", Strict: true}
+	require.NoError(t, env.Register((*storage)(nil), "mode", modeStorage("")))
+
+	config := &storageConfig{Backend: modeStorage("fast")}
+	pairs, err := env.Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{"APP_NAME": "", "APP_BACKEND": "fast", "APP_BACKEND_TYPE": "mode"}, pairs)
+
+	parsed := &storageConfig{}
+	_, err = env.UnmarshalMap(pairs, parsed)
+	require.NoError(t, err, "the member's own variable must be used")
+	assert.Equal(modeStorage("fast"), parsed.Backend, "single value types must parse their own variable")
+	require.NoError(t, env.RoundTrip(config))
+}
+
+func TestDescribeRegistered(t *testing.T) {
+	t.Parallel()
+
+	vars, err := storageENV(t).Describe(&storageConfig{})
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, info := range vars {
+		names = append(names, info.Name)
+	}
+
+	assert.Equal(t, []string{
+		"APP_NAME",
+		"APP_BACKEND_TYPE", "APP_BACKEND_PATH", "APP_BACKEND_BUCKET", "APP_BACKEND_REGION", "APP_MIRROR_KEYS",
+		"APP_MIRROR_{key}_TYPE", "APP_MIRROR_{key}_PATH", "APP_MIRROR_{key}_BUCKET", "APP_MIRROR_{key}_REGION",
+	}, names)
+	assert.Equal(t, []string{"file", "s3"}, vars[1].Values)
+	assert.Equal(t, "storageConfig.Backend.(*cnfg_test.s3Storage).Bucket", vars[3].Path)
+}
@@ -0,0 +1,77 @@
+package cnfg
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+/* This file contains the logic to report where each struct member's value came from. */
+
+// Origin describes where a struct member's value came from.
+type Origin string
+
+// These are the possible origins of a struct member's value.
+const (
+	OriginEnv     Origin = "env"     // An env variable set the value.
+	OriginDefault Origin = "default" // A struct tag default set the value.
+	OriginKept    Origin = "kept"    // The member kept the value it had before parsing.
+)
+
+// Source is the origin of a single struct member's value.
+type Source struct {
+	Origin Origin `json:"origin"`
+	// Var is the env variable that set the value. When the value was not
+	// set by an env variable, this is the variable that would set it.
+	Var string `json:"var"`
+}
+
+// Report maps the Go path of every struct member, ie. Config.Shelter.People[2].Age,
+// to the source of its value. It can be marshaled to JSON.
+type Report map[string]Source
+
+// UnmarshalWithReport parses environment variables into the provided interface,
+// just like Unmarshal. It returns a report of where every struct member's value
+// came from. The report is returned even if there is an error.
+func (e *ENV) UnmarshalWithReport(i any) (Report, error) {
+	value := reflect.ValueOf(i)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return nil, ErrInvalidInterface
+	}
+
+	if e.Tag == "" {
+		e.Tag = ENVTag
+	}
+
+	parse := e.newParser(MapEnvPairs(e.Pfx, os.Environ()))
+	parse.Report = make(Report)
+	_, err := parse.Run(value, e.Pfx)
+
+	return parse.Report, err
+}
+
+// record saves the source of a value. Only values that cannot be split into
+// more members are recorded, except empty slices and maps.
+func (p *parser) record(field reflect.Value, tag string, exists bool) {
+	leaf := isLeaf(field.Type()) && p.Types[field.Type()] == nil // registered interfaces are recorded by member.
+
+	switch {
+	case exists && leaf && p.Defaults:
+		p.Report[p.Path] = Source{Origin: OriginDefault, Var: tag}
+	case exists && leaf:
+		p.Report[p.Path] = Source{Origin: OriginEnv, Var: tag}
+	case leaf, (field.Kind() == reflect.Slice || field.Kind() == reflect.Map) && field.Len() == 0:
+		if _, ok := p.Report[p.Path]; !ok {
+			p.Report[p.Path] = Source{Origin: OriginKept, Var: tag}
+		}
+	}
+}
+
+// forget removes a path and all of its children from the report.
+func (r Report) forget(path string) {
+	for key := range r {
+		if key == path || strings.HasPrefix(key, path+".") || strings.HasPrefix(key, path+"[") {
+			delete(r, key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cnfg_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+func TestUnmarshalWithReport(t *testing.T) { //nolint:paralleltest // cannot parallel env vars.
+	type person struct {
+		Name string `xml:"name"`
+		Age  int    `xml:"age,default=18"`
+	}
+
+	type reportConfig struct {
+		Title   string            `xml:"title"`
+		Timeout time.Duration     `xml:"timeout,default=10s"`
+		Time    time.Time         `xml:"time"`
+		People  []person          `xml:"people"`
+		Users   []string          `xml:"users,default=me;you"`
+		Labels  map[string]string `xml:"labels"`
+		Empty   map[string]string `xml:"empty"`
+	}
+
+	t.Setenv("RPT_TITLE", "shelter")
+	t.Setenv("RPT_PEOPLE_1_NAME", "you")
+	t.Setenv("RPT_LABELS_team", "ops")
+
+	assert := assert.New(t)
+	config := &reportConfig{People: []person{{Name: "me", Age: 1}, {}}}
+
+	report, err := (&cnfg.ENV{Pfx: "RPT"}).UnmarshalWithReport(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Report{
+		"reportConfig.Title":          {Origin: cnfg.OriginEnv, Var: "RPT_TITLE"},
+		"reportConfig.Timeout":        {Origin: cnfg.OriginDefault, Var: "RPT_TIMEOUT"},
+		"reportConfig.Time":           {Origin: cnfg.OriginKept, Var: "RPT_TIME"},
+		"reportConfig.People[0].Name": {Origin: cnfg.OriginKept, Var: "RPT_PEOPLE_0_NAME"},
+		"reportConfig.People[0].Age":  {Origin: cnfg.OriginKept, Var: "RPT_PEOPLE_0_AGE"},
+		"reportConfig.People[1].Name": {Origin: cnfg.OriginEnv, Var: "RPT_PEOPLE_1_NAME"},
+		"reportConfig.People[1].Age":  {Origin: cnfg.OriginDefault, Var: "RPT_PEOPLE_1_AGE"},
+		"reportConfig.Users[0]":       {Origin: cnfg.OriginDefault, Var: "RPT_USERS_0"},
+		"reportConfig.Users[1]":       {Origin: cnfg.OriginDefault, Var: "RPT_USERS_1"},
+		"reportConfig.Labels[team]":   {Origin: cnfg.OriginEnv, Var: "RPT_LABELS_team"},
+		"reportConfig.Empty":          {Origin: cnfg.OriginKept, Var: "RPT_EMPTY"},
+	}, report)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(string(data), `"reportConfig.Title":{"origin":"env","var":"RPT_TITLE"}`)
+
+	_, err = (&cnfg.ENV{}).UnmarshalWithReport(*config)
+	require.ErrorIs(t, err, cnfg.ErrInvalidInterface)
+}
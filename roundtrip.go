@@ -0,0 +1,237 @@
+package cnfg
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+/* This file contains the logic to prove a struct survives being marshaled and parsed. */
+
+// RoundTrip marshals a struct pointer with Marshal, parses the variables into a
+// new value of the same type with UnmarshalMap, and compares the two. Every member
+// that did not survive is returned as a *FieldError that wraps ErrRoundTrip. More
+// than one is returned as Errors. Parse errors are returned as they are.
+//
+// Nil and empty slices, maps and pointers are equal, because env variables cannot
+// tell them apart. Types with a MarshalText method are compared as text, and types
+// with an Equal method, like time.Time, are compared with it.
+func (e *ENV) RoundTrip(i any) error {
+	value := reflect.ValueOf(i)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return ErrInvalidInterface
+	}
+
+	pairs, err := e.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	parsed := reflect.New(value.Elem().Type())
+	parse := e.newParser(pairs)
+	parse.Report = make(Report) // The report has the variable name for every member path.
+
+	if _, err := parse.Run(parsed, e.Pfx); err != nil {
+		return err
+	}
+
+	diff := &differ{Tag: e.Tag, Low: e.Low, Namer: e.namer(), Report: parse.Report}
+	diff.Value(value.Elem(), parsed.Elem(), parse.Path)
+
+	switch len(diff.Errs) {
+	case 0:
+		return nil
+	case 1:
+		return diff.Errs[0]
+	default:
+		return diff.Errs
+	}
+}
+
+// differ compares a struct to the same struct after it was marshaled and parsed.
+type differ struct {
+	Tag    string   // struct tag to look for on struct members
+	Low    bool     // allow lowercase variables
+	Namer  NameFunc // names members without a tag name
+	Report Report   // variable names for member paths
+	Errs   Errors   // every member that changed
+}
+
+// Value compares two values of the same type, and saves an error if they are not equal.
+func (d *differ) Value(want, got reflect.Value, path string) { //nolint:cyclop
+	if equal, ok := methodEqual(want, got); ok {
+		if !equal {
+			d.changed(want, got, path)
+		}
+
+		return
+	}
+
+	switch want.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if want.IsNil() || got.IsNil() {
+			d.nilValue(want, got, path)
+		} else if want.Kind() == reflect.Interface && want.Elem().Type() != got.Elem().Type() {
+			d.changed(want, got, path)
+		} else {
+			d.Value(addressable(want.Elem()), addressable(got.Elem()), path)
+		}
+	case reflect.Struct:
+		d.Struct(want, got, path)
+	case reflect.Slice, reflect.Array:
+		if want.Len() != got.Len() {
+			d.changed(want, got, path)
+			return
+		}
+
+		for idx := range want.Len() {
+			d.Value(addressable(want.Index(idx)), addressable(got.Index(idx)), fmt.Sprintf("%s[%d]", path, idx))
+		}
+	case reflect.Map:
+		d.Map(want, got, path)
+	case reflect.Float32, reflect.Float64:
+		if want.Float() != got.Float() && !(math.IsNaN(want.Float()) && math.IsNaN(got.Float())) {
+			d.changed(want, got, path)
+		}
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return // These cannot be marshaled.
+	default:
+		if !reflect.DeepEqual(want.Interface(), got.Interface()) {
+			d.changed(want, got, path)
+		}
+	}
+}
+
+// Struct compares every exported struct member that is not skipped with "-".
+func (d *differ) Struct(want, got reflect.Value, path string) {
+	for idx := range want.NumField() {
+		member := want.Type().Field(idx)
+		if name, _ := memberName(member, d.Tag, d.Low, d.Namer); !member.IsExported() || name == "-" {
+			continue
+		}
+
+		d.Value(want.Field(idx), got.Field(idx), path+"."+member.Name)
+	}
+}
+
+// Map compares every key in both maps, in sorted order.
+func (d *differ) Map(want, got reflect.Value, path string) {
+	keys := want.MapKeys()
+	for _, key := range got.MapKeys() {
+		if !want.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.SortFunc(keys, compareKeys)
+
+	for _, key := range keys {
+		wantVal, gotVal := want.MapIndex(key), got.MapIndex(key)
+		kpath := fmt.Sprintf("%s[%v]", path, key)
+
+		if !wantVal.IsValid() || !gotVal.IsValid() {
+			d.changed(wantVal, gotVal, kpath)
+			continue
+		}
+
+		d.Value(addressable(wantVal), addressable(gotVal), kpath)
+	}
+}
+
+// nilValue compares pointers and interfaces when one or both are nil.
+// A nil value is equal to a pointer to a zero value.
+func (d *differ) nilValue(want, got reflect.Value, path string) {
+	if want.IsNil() && got.IsNil() {
+		return
+	}
+
+	if want.Kind() == reflect.Ptr && (want.IsNil() && got.Elem().IsZero() || got.IsNil() && want.Elem().IsZero()) {
+		return
+	}
+
+	d.changed(want, got, path)
+}
+
+// changed saves an error for a member that did not survive the round trip.
+func (d *differ) changed(want, got reflect.Value, path string) {
+	field := &FieldError{Var: d.Report[path].Var, Path: path, Value: display(got)}
+	if field.Var == "" {
+		field.Var = path
+	}
+
+	if want.IsValid() {
+		field.Type = want.Type()
+	}
+
+	field.Err = fmt.Errorf("%w: marshaled %s, parsed %s", ErrRoundTrip, display(want), field.Value)
+	d.Errs = append(d.Errs, field)
+}
+
+// methodEqual compares values with their MarshalText or Equal method.
+// The boolean is false if the type has neither.
+func methodEqual(want, got reflect.Value) (bool, bool) {
+	if !want.CanAddr() || !got.CanAddr() {
+		return false, false
+	}
+
+	if wantText, ok := want.Addr().Interface().(encoding.TextMarshaler); ok {
+		gotText, _ := got.Addr().Interface().(encoding.TextMarshaler)
+		wantB, wantErr := wantText.MarshalText()
+		gotB, gotErr := gotText.MarshalText()
+
+		return string(wantB) == string(gotB) && (wantErr == nil) == (gotErr == nil), true
+	}
+
+	if method := want.Addr().MethodByName("Equal"); method.IsValid() &&
+		method.Type().NumIn() == 1 && method.Type().In(0) == want.Type() &&
+		method.Type().NumOut() == 1 && method.Type().Out(0).Kind() == reflect.Bool {
+		return method.Call([]reflect.Value{got})[0].Bool(), true
+	}
+
+	return false, false
+}
+
+// addressable returns an addressable copy of a value, so its pointer methods may be used.
+func addressable(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value
+	}
+
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+
+	return copied
+}
+
+// display formats a value for an error message. Slice items and map entries are
+// quoted one at a time, so []string{""} and []string{} look different.
+func display(value reflect.Value) string {
+	switch {
+	case !value.IsValid():
+		return "nothing"
+	case value.Kind() == reflect.Ptr && !value.IsNil():
+		return display(value.Elem())
+	case (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Type().Elem().Kind() != reflect.Uint8:
+		items := make([]string, value.Len())
+		for idx := range items {
+			items[idx] = display(value.Index(idx))
+		}
+
+		return "[" + strings.Join(items, " ") + "]"
+	case value.Kind() == reflect.Map:
+		keys := value.MapKeys()
+		slices.SortFunc(keys, compareKeys)
+
+		items := make([]string, len(keys))
+		for idx, key := range keys {
+			items[idx] = display(key) + ":" + display(value.MapIndex(key))
+		}
+
+		return "map[" + strings.Join(items, " ") + "]"
+	default:
+		return fmt.Sprintf("%q", fmt.Sprint(value.Interface()))
+	}
+}
@@ -0,0 +1,112 @@
+package cnfg_test
+
+import (
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type roundTripUser struct {
+	Name  string   `xml:"name"`
+	Pass  string   `xml:"pass,secret"`
+	Roles []string `xml:"role"`
+}
+
+type roundTripConfig struct {
+	Title   string                   `xml:"title"`
+	Created time.Time                `xml:"created"`
+	IP      net.IP                   `xml:"ip"`
+	Timeout cnfg.Duration            `xml:"timeout"`
+	Ratio   float64                  `xml:"ratio"`
+	Users   []*roundTripUser         `xml:"user"`
+	Limits  map[string]uint          `xml:"limit"`
+	Empty   []string                 `xml:"empty"`
+	Nested  map[string]roundTripUser `xml:"nested"`
+	Ignored func()                   `xml:"-"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	config := &roundTripConfig{
+		Title:   "round trip",
+		Created: time.Date(2024, 2, 29, 12, 30, 0, 5, time.FixedZone("X", 3600)),
+		IP:      net.ParseIP("10.1.2.3"),
+		Timeout: cnfg.Duration{Duration: time.Minute},
+		Ratio:   math.NaN(),
+		Users:   []*roundTripUser{{Name: "me", Pass: "secret", Roles: []string{"admin"}}, {Name: "you"}},
+		Limits:  map[string]uint{"cpu": 2, "mem": 1 << 30},
+		Empty:   []string{},
+		Ignored: func() {},
+	}
+
+	require.NoError(t, (&cnfg.ENV{Pfx: "APP"}).RoundTrip(config))
+	require.ErrorIs(t, (&cnfg.ENV{}).RoundTrip(*config), cnfg.ErrInvalidInterface)
+
+	err := (&cnfg.ENV{Pfx: "APP", Redact: true}).RoundTrip(config)
+	require.ErrorIs(t, err, cnfg.ErrRoundTrip, "redacted values do not survive")
+
+	var fieldErr *cnfg.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "APP_USER_0_PASS", fieldErr.Var)
+	assert.Equal(t, "roundTripConfig.Users[0].Pass", fieldErr.Path)
+	assert.Equal(t, `"***"`, fieldErr.Value)
+
+	config.Users[1].Pass = "another"
+	err = (&cnfg.ENV{Pfx: "APP", Redact: true}).RoundTrip(config)
+
+	var errs cnfg.Errors
+
+	require.ErrorAs(t, err, &errs, "every member that did not survive must be returned")
+	assert.Len(t, errs, 2)
+
+	value := ""
+	err = (&cnfg.ENV{Pfx: "APP"}).RoundTrip(&struct {
+		Keys []*string `xml:"keys"`
+	}{Keys: []*string{nil, &value}})
+	require.ErrorIs(t, err, cnfg.ErrRoundTrip, "a nil item is not marshaled, so the next item is not parsed")
+	assert.Contains(t, err.Error(), `marshaled ["<nil>" ""], parsed []`, "slice items must be quoted")
+}
+
+// FuzzRoundTrip proves that any values in these types survive being marshaled and parsed.
+func FuzzRoundTrip(f *testing.F) {
+	f.Add("name", int64(-1), uint32(1), 1.5, true, "key", "value", int64(time.Second))
+	f.Add("", int64(0), uint32(0), 0.0, false, "", "", int64(0))
+	f.Add("with\nnewline=and;symbols", int64(math.MinInt64), uint32(math.MaxUint32),
+		math.Inf(-1), true, "with_separator", "'quoted'", int64(math.MaxInt64))
+
+	type fuzzed struct {
+		Str   string            `xml:"str"`
+		Int   int64             `xml:"int"`
+		Uint  *uint32           `xml:"uint"`
+		Float float64           `xml:"float"`
+		Bool  bool              `xml:"bool"`
+		Dur   time.Duration     `xml:"dur"`
+		List  []string          `xml:"list"`
+		Map   map[string]string `xml:"map"`
+		Sub   struct {
+			Ints [2]int64 `xml:"ints"`
+		} `xml:"sub"`
+	}
+
+	f.Fuzz(func(t *testing.T, str string, num int64, unum uint32, flt float64, boolean bool, key, val string, dur int64) {
+		config := &fuzzed{
+			Str: str, Int: num, Uint: &unum, Float: flt, Bool: boolean, Dur: time.Duration(dur),
+			List: []string{str, val}, Map: map[string]string{key: val},
+		}
+		config.Sub.Ints = [2]int64{num, dur}
+
+		// An empty key is the map's own variable, and a blank value deletes it.
+		if key == "" || val == "" {
+			config.Map = nil
+		}
+
+		require.NoError(t, (&cnfg.ENV{Pfx: "APP"}).RoundTrip(config))
+	})
+}
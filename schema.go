@@ -0,0 +1,260 @@
+package cnfg
+
+import (
+	"encoding"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* This file contains the logic to generate a JSON Schema from a struct type. */
+
+// SchemaDraft is the JSON Schema dialect used by ENV.Schema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// DurationPattern matches the values accepted by time.ParseDuration.
+const DurationPattern = `^[-+]?(0|((\d+(\.\d*)?|\.\d+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// Schema is a JSON Schema (draft 2020-12) document. Marshal it with encoding/json.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+}
+
+// Schema generates a JSON Schema for a struct type. Property names follow the
+// same struct tag (ENV.Tag) as the env variables, so the schema validates config
+// files that are decoded into the same struct. Members with a `required` tag
+// option are required, `default=` values are included, and `secret` members are
+// marked writeOnly. Pass a struct or a pointer to one.
+func (e *ENV) Schema(i any) (*Schema, error) {
+	typ := reflect.TypeOf(i)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, ErrInvalidInterface
+	}
+
+	if e.Tag == "" {
+		e.Tag = ENVTag
+	}
+
+	gen := &schemer{Low: e.Low, Tag: e.Tag, Root: typ, Seen: make(map[reflect.Type]bool)}
+
+	schema, err := gen.Anything(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	schema.Schema = SchemaDraft
+	schema.Title = typ.Name()
+
+	return schema, nil
+}
+
+type schemer struct {
+	Low  bool                  // allow lowercase variables?
+	Tag  string                // struct tag to look for on struct members
+	Root reflect.Type          // the struct type that the schema is for
+	Seen map[reflect.Type]bool // struct types being generated, to avoid infinite recursion
+}
+
+// Anything returns the schema for any type.
+func (s *schemer) Anything(typ reflect.Type) (*Schema, error) { //nolint:cyclop
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == reflect.TypeFor[time.Duration](), typ == reflect.TypeFor[Duration]():
+		return &Schema{Type: "string", Pattern: DurationPattern}, nil
+	case typ == reflect.TypeFor[time.Time]():
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case reflect.PointerTo(typ).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()),
+		typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+		return &Schema{Type: "string"}, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: new(int)}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Complex64, reflect.Complex128:
+		return &Schema{Type: "string"}, nil
+	case reflect.Slice:
+		items, err := s.Anything(typ.Elem())
+		return &Schema{Type: "array", Items: items}, err
+	case reflect.Array:
+		size := typ.Len()
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", MaxLength: &size}, nil
+		}
+
+		items, err := s.Anything(typ.Elem())
+
+		return &Schema{Type: "array", Items: items, MaxItems: &size}, err
+	case reflect.Map:
+		values, err := s.Anything(typ.Elem())
+		return &Schema{Type: "object", AdditionalProperties: values}, err
+	case reflect.Struct:
+		return s.Struct(typ)
+	default:
+		return &Schema{}, nil // Any value.
+	}
+}
+
+// Struct returns the schema for a struct type.
+func (s *schemer) Struct(typ reflect.Type) (*Schema, error) {
+	if s.Seen[typ] {
+		if typ == s.Root {
+			return &Schema{Ref: "#"}, nil
+		}
+
+		return &Schema{Type: "object"}, nil // This struct contains itself.
+	}
+
+	s.Seen[typ] = true
+	defer delete(s.Seen, typ)
+
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for idx := range typ.NumField() {
+		member := typ.Field(idx)
+		if !member.IsExported() {
+			continue
+		}
+
+		name, opts := parseTag(member.Tag.Get(s.Tag))
+		if name == "-" {
+			continue
+		}
+
+		prop, err := s.Anything(member.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "" && member.Anonymous && prop.Type == "object" && prop.Properties != nil {
+			// Embedded structs without a name are flattened into their parent.
+			maps.Copy(schema.Properties, prop.Properties)
+
+			schema.Required = append(schema.Required, prop.Required...)
+
+			continue
+		}
+
+		if name == "" {
+			name = member.Name
+		}
+
+		if err := s.annotate(prop, member, opts); err != nil {
+			return nil, err
+		}
+
+		if opts.Required {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = prop
+	}
+
+	return schema, nil
+}
+
+// annotate adds the description, default value and secret flag to a member's schema.
+func (s *schemer) annotate(prop *Schema, member reflect.StructField, opts tagOpts) error {
+	prop.Description = member.Tag.Get(DescTag)
+	if prop.Description == "" {
+		prop.Description = member.Tag.Get(HelpTag)
+	}
+
+	prop.WriteOnly = opts.Secret
+
+	if opts.Default == "" {
+		return nil
+	}
+
+	// Parse the default the same way the env parser does, then convert it to JSON.
+	value := reflect.New(member.Type).Elem()
+	parse := &parser{Low: s.Low, Tag: s.Tag, Path: member.Name, Size: opts.Size, Sep: LevelSeparator}
+
+	if _, err := parse.Default(value, member.Name, opts.Default); err != nil {
+		return err
+	}
+
+	prop.Default = schemaValue(value)
+
+	return nil
+}
+
+// schemaValue converts a parsed default value into something that marshals into
+// JSON that matches its schema.
+func schemaValue(value reflect.Value) any {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Type() == reflect.TypeFor[time.Duration]():
+		return time.Duration(value.Int()).String()
+	case value.Kind() == reflect.Complex64, value.Kind() == reflect.Complex128:
+		return strconv.FormatComplex(value.Complex(), 'f', -1, value.Type().Bits())
+	case value.CanAddr() && value.Addr().Type().Implements(reflect.TypeFor[encoding.TextMarshaler]()):
+		text, _ := value.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text)
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return string(value.Bytes())
+	case value.Kind() == reflect.Array && value.Type().Elem().Kind() == reflect.Uint8:
+		bytes := make([]byte, value.Len())
+		for i := range bytes {
+			bytes[i] = byte(value.Index(i).Uint())
+		}
+
+		return strings.TrimRight(string(bytes), "\x00")
+	case value.Kind() == reflect.Slice, value.Kind() == reflect.Array:
+		list := make([]any, value.Len())
+		for i := range list {
+			list[i] = schemaValue(value.Index(i))
+		}
+
+		return list
+	case value.Kind() == reflect.Map:
+		obj := make(map[string]any, value.Len())
+		for i := value.MapRange(); i.Next(); {
+			obj[fmt.Sprint(i.Key())] = schemaValue(i.Value())
+		}
+
+		return obj
+	default:
+		return value.Interface()
+	}
+}
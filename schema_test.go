@@ -0,0 +1,108 @@
+package cnfg_test
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type SchemaBase struct {
+	ID uint `xml:"id,required"`
+}
+
+type schemaConfig struct {
+	SchemaBase
+	Name     string         `xml:"name,default=app" desc:"Application name."`
+	Timeout  time.Duration  `xml:"timeout,default=90s"`
+	Interval cnfg.Duration  `xml:"interval"`
+	Started  time.Time      `xml:"started"`
+	IP       net.IP         `xml:"ip"`
+	Ratio    *float64       `xml:"ratio,default=0.5"`
+	Users    []string       `xml:"users,default=me;you"`
+	Levels   map[string]int `xml:"levels,default=low=1"`
+	Token    string         `xml:"token,secret,required"`
+	Servers  map[string]*struct {
+		Host string `xml:"host"`
+	} `xml:"servers"`
+	Child   *schemaConfig `xml:"child"`
+	Untag   bool
+	Ignored string `xml:"-"`
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+
+	schema, err := (&cnfg.ENV{}).Schema(schemaConfig{})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	expect := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "schemaConfig",
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "minimum": 0},
+			"name": {"type": "string", "description": "Application name.", "default": "app"},
+			"timeout": {"type": "string", "pattern": PATTERN, "default": "1m30s"},
+			"interval": {"type": "string", "pattern": PATTERN},
+			"started": {"type": "string", "format": "date-time"},
+			"ip": {"type": "string"},
+			"ratio": {"type": "number", "default": 0.5},
+			"users": {"type": "array", "items": {"type": "string"}, "default": ["me", "you"]},
+			"levels": {"type": "object", "additionalProperties": {"type": "integer"}, "default": {"low": 1}},
+			"token": {"type": "string", "writeOnly": true},
+			"servers": {
+				"type": "object",
+				"additionalProperties": {"type": "object", "properties": {"host": {"type": "string"}}}
+			},
+			"child": {"$ref": "#"},
+			"Untag": {"type": "boolean"}
+		},
+		"required": ["id", "token"]
+	}`
+
+	pattern, _ := json.Marshal(cnfg.DurationPattern)
+	assert.JSONEq(strings.ReplaceAll(expect, "PATTERN", string(pattern)), string(data))
+
+	for _, dur := range []string{"0", "1m30s", "-1.5h", "300ms", "2µs", ".5s"} {
+		assert.Regexp(cnfg.DurationPattern, dur)
+	}
+
+	for _, dur := range []string{"", "1", "1m 30s", "abc"} {
+		assert.NotRegexp(cnfg.DurationPattern, dur)
+	}
+
+	_, err = (&cnfg.ENV{}).Schema(1)
+	require.ErrorIs(t, err, cnfg.ErrInvalidInterface)
+
+	_, err = (&cnfg.ENV{}).Schema(&struct {
+		Bad int `xml:"bad,default=x"`
+	}{})
+	require.Error(t, err, "an invalid default must return an error")
+}
+
+func TestSchemaArray(t *testing.T) {
+	t.Parallel()
+
+	schema, err := (&cnfg.ENV{}).Schema(struct {
+		Hash   [32]byte   `xml:"hash"`
+		Coords [3]float64 `xml:"coord,default=1;2.5"`
+	}{})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(schema.Properties)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"hash": {"type": "string", "maxLength": 32},
+		"coord": {"type": "array", "items": {"type": "number"}, "maxItems": 3, "default": [1, 2.5, 0]}
+	}`, string(data))
+}
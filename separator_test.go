@@ -0,0 +1,73 @@
+package cnfg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type sepConfig struct {
+	MaxConns int               `xml:"max_conns"`
+	DB       sepDB             `xml:"db"`
+	Hosts    []string          `xml:"hosts,default=a;b"`
+	Labels   map[string]string `xml:"labels"`
+}
+
+type sepDB struct {
+	MaxConns int `xml:"max_conns,required"`
+	Max      struct {
+		Conns int `xml:"conns"`
+	} `xml:"max"`
+}
+
+func TestNestedSeparator(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := &cnfg.ENV{Pfx: "APP", Sep: cnfg.NestedSeparator, Strict: true}
+	pairs := map[string]string{
+		"APP__MAX_CONNS":         "1",
+		"APP__DB__MAX_CONNS":     "2",
+		"APP__DB__MAX__CONNS":    "3",
+		"APP__LABELS__team_name": "ops",
+		"APP__LABELS__env":       "prod",
+		"APP__DB__MAX_CONNZ":     "4",
+	}
+	config := &sepConfig{}
+
+	_, err := env.UnmarshalMap(pairs, config)
+
+	var unknown *cnfg.UnknownError
+
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal([]cnfg.UnknownVar{{Name: "APP__DB__MAX_CONNZ", Suggest: "APP__DB__MAX_CONNS"}}, unknown.Vars)
+	assert.Equal(1, config.MaxConns)
+	assert.Equal(2, config.DB.MaxConns, "single underscores must stay inside names")
+	assert.Equal(3, config.DB.Max.Conns)
+	assert.Equal(map[string]string{"team_name": "ops", "env": "prod"}, config.Labels)
+	assert.Equal([]string{"a", "b"}, config.Hosts, "defaults must use the separator")
+
+	marshaled, err := env.Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{
+		"APP__MAX_CONNS":         "1",
+		"APP__DB__MAX_CONNS":     "2",
+		"APP__DB__MAX__CONNS":    "3",
+		"APP__HOSTS__0":          "a",
+		"APP__HOSTS__1":          "b",
+		"APP__LABELS__team_name": "ops",
+		"APP__LABELS__env":       "prod",
+	}, marshaled)
+	require.NoError(t, (&cnfg.ENV{Pfx: "APP", Sep: cnfg.NestedSeparator}).RoundTrip(config))
+
+	vars, err := env.Describe(config)
+	require.NoError(t, err)
+	assert.Equal("APP__HOSTS__LEN", vars[3].Name)
+	assert.Equal("APP__HOSTS__{n}", vars[4].Name)
+
+	_, err = env.UnmarshalMap(map[string]string{}, &sepConfig{})
+	require.ErrorIs(t, err, cnfg.ErrRequired)
+	assert.Contains(err.Error(), "APP__DB__MAX_CONNS")
+}
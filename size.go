@@ -0,0 +1,119 @@
+package cnfg
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/* This file contains the logic to parse and format byte sizes for the `size` tag option. */
+
+// sizeUnit is a byte size suffix and the number of bytes it represents.
+type sizeUnit struct {
+	Name  string
+	Bytes uint64
+}
+
+// sizeUnits are sorted from largest to smallest, so the largest unit is used when formatting.
+var sizeUnits = []sizeUnit{ //nolint:gochecknoglobals
+	{"EiB", 1 << 60}, {"EB", 1e18},
+	{"PiB", 1 << 50}, {"PB", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6},
+	{"KiB", 1 << 10}, {"KB", 1e3},
+	{"B", 1},
+}
+
+// isInteger returns true for every integer kind.
+func isInteger(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseSize parses a byte size like 10MiB or 1.5GB into an integer member.
+// The unit is not case sensitive, and the B may be left off: 10Mi, 1.5g.
+func parseSize(field reflect.Value, envval string) error {
+	idx := strings.IndexFunc(envval, unicode.IsLetter)
+	if idx == -1 {
+		idx = len(envval)
+	}
+
+	num, unit := strings.TrimSpace(envval[:idx]), strings.ToLower(envval[idx:])
+	if !strings.HasSuffix(unit, "b") {
+		unit += "b" // A plain number is bytes.
+	}
+
+	size, ok := new(big.Rat).SetString(num)
+	if !ok || num == "" || strings.Contains(num, "/") {
+		return fmt.Errorf("%w: %s", ErrInvalidSize, envval)
+	}
+
+	for _, u := range sizeUnits {
+		if unit == strings.ToLower(u.Name) {
+			size.Mul(size, new(big.Rat).SetInt(new(big.Int).SetUint64(u.Bytes)))
+			return setSize(field, size, envval)
+		}
+	}
+
+	return fmt.Errorf("%w: unknown unit: %s", ErrInvalidSize, envval)
+}
+
+// setSize sets a parsed size into an integer member, if it fits.
+func setSize(field reflect.Value, size *big.Rat, envval string) error {
+	if !size.IsInt() {
+		return fmt.Errorf("%w: not a whole number of bytes: %s", ErrInvalidSize, envval)
+	}
+
+	switch val := size.Num(); field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !val.IsInt64() || field.OverflowInt(val.Int64()) {
+			return fmt.Errorf("parsing size %s: %w", envval, strconv.ErrRange)
+		}
+
+		field.SetInt(val.Int64())
+	default:
+		if !val.IsUint64() || field.OverflowUint(val.Uint64()) {
+			return fmt.Errorf("parsing size %s: %w", envval, strconv.ErrRange)
+		}
+
+		field.SetUint(val.Uint64())
+	}
+
+	return nil
+}
+
+// formatSize formats an integer member with the largest unit that divides it evenly.
+func formatSize(field reflect.Value) string {
+	var (
+		bytes uint64
+		sign  string
+	)
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Int() < 0 {
+			sign = "-"
+		}
+
+		bytes = new(big.Int).Abs(big.NewInt(field.Int())).Uint64()
+	default:
+		bytes = field.Uint()
+	}
+
+	for _, u := range sizeUnits[:len(sizeUnits)-1] {
+		if bytes != 0 && bytes%u.Bytes == 0 {
+			return sign + strconv.FormatUint(bytes/u.Bytes, base10) + u.Name
+		}
+	}
+
+	return sign + strconv.FormatUint(bytes, base10)
+}
@@ -0,0 +1,61 @@
+package cnfg_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type sizeTest struct {
+	Buffer  int64    `xml:"buffer,size"`
+	Max     uint32   `xml:"max,size,default=1.5GB"`
+	Chunks  []uint16 `xml:"chunks,size"`
+	Count   int      `xml:"count"`
+	Missing int8     `xml:"missing,size"`
+}
+
+func TestSize(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	pairs := map[string]string{
+		"APP_BUFFER":   "10MiB",
+		"APP_CHUNKS_0": "1k",
+		"APP_CHUNKS_1": "63 KiB",
+		"APP_CHUNKS_2": "512",
+		"APP_COUNT":    "0x10",
+	}
+	config := &sizeTest{}
+
+	worked, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.True(worked)
+	assert.Equal(int64(10<<20), config.Buffer)
+	assert.Equal(uint32(1_500_000_000), config.Max, "defaults must be parsed as sizes")
+	assert.Equal([]uint16{1000, 63 << 10, 512}, config.Chunks, "slice items must be parsed as sizes")
+	assert.Equal(16, config.Count)
+
+	marshaled, err := (&cnfg.ENV{Pfx: "APP"}).Marshal(config)
+	require.NoError(t, err)
+	assert.Equal("10MiB", marshaled["APP_BUFFER"])
+	assert.Equal("1500MB", marshaled["APP_MAX"])
+	assert.Equal("1KB", marshaled["APP_CHUNKS_0"])
+	assert.Equal("63KiB", marshaled["APP_CHUNKS_1"])
+	assert.Equal("512", marshaled["APP_CHUNKS_2"])
+	assert.Equal("16", marshaled["APP_COUNT"], "members without the size option must be plain integers")
+	assert.Equal("0", marshaled["APP_MISSING"])
+
+	for envval, wantErr := range map[string]error{
+		"1.5B":  cnfg.ErrInvalidSize,
+		"10QB":  cnfg.ErrInvalidSize,
+		"MiB":   cnfg.ErrInvalidSize,
+		"1KiB":  strconv.ErrRange,
+		"-1KiB": strconv.ErrRange,
+	} {
+		_, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(map[string]string{"APP_MISSING": envval}, &sizeTest{})
+		require.ErrorIs(t, err, wantErr, envval)
+	}
+}
@@ -0,0 +1,107 @@
+package cnfg
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/* This file contains the logic to parse slice lengths, sparse indexes and deleted items. */
+
+// Sparse is the policy for slice indexes past the end of a slice, after a missing index.
+type Sparse uint8
+
+// Sparse policies for slice indexes that skip a number: APP_USERS_5 for a slice with 3 items.
+const (
+	// SparseIgnore stops parsing a slice at the first missing index. Later indexes are
+	// not parsed, and are reported as unknown in strict mode.
+	SparseIgnore Sparse = iota
+	// SparseFill parses every index, and fills the missing indexes with zero values.
+	SparseFill
+	// SparseError returns a *FieldError wrapping ErrSliceIndex for an index after a missing index.
+	SparseError
+)
+
+// LenVar is appended to a slice member's variable name to set the length of the slice
+// before the indexed variables are parsed. APP_USERS_LEN=1 keeps the first item, and
+// APP_USERS_LEN=0 APP_USERS_0=me replaces the whole slice. A longer length adds zero values.
+// This is upper-cased unless ENV.Low is true.
+const LenVar = "len"
+
+// MaxSliceLen limits the slice length from a LenVar variable, and the last index
+// filled with SparseFill, so one variable cannot allocate too much memory. Larger
+// values return a *FieldError wrapping ErrSliceLen. Existing slices may be longer.
+const MaxSliceLen = 10000
+
+// sliceLen truncates or grows a slice to the length in its LenVar variable, if it exists.
+func (p *parser) sliceLen(field reflect.Value, tag string, delenv bool) (bool, error) {
+	ltag := specialVar(tag, p.Sep, LenVar, p.Low)
+
+	envval, exists := p.lookup(ltag)
+	if !exists {
+		return false, nil
+	}
+
+	if delenv {
+		_ = os.Unsetenv(ltag) // delete it if it was requested in the env tag.
+	}
+
+	size, err := strconv.Atoi(envval)
+	if err == nil && size < 0 {
+		err = fmt.Errorf("%w: negative length", strconv.ErrRange)
+	}
+
+	if err == nil && size > max(field.Len(), MaxSliceLen) {
+		err = fmt.Errorf("%w: length %d, maximum is %d", ErrSliceLen, size, MaxSliceLen)
+	}
+
+	if err != nil {
+		return false, p.fieldError(field, ltag, envval, err)
+	}
+
+	if size <= field.Len() {
+		// Limit the capacity, so appended items do not overwrite the original slice.
+		field.Set(field.Slice3(0, size, size))
+	} else {
+		field.Set(reflect.AppendSlice(field, reflect.MakeSlice(field.Type(), size-field.Len(), size-field.Len())))
+	}
+
+	return true, nil
+}
+
+// lastIndex returns the largest index with a variable under a slice, and the variable
+// name. The index is -1 when the slice has no indexed variables.
+func (p *parser) lastIndex(tag string) (int, string) {
+	last, name := -1, ""
+
+	for key := range p.Vals {
+		if !strings.HasPrefix(key, tag+p.Sep) {
+			continue
+		}
+
+		index, _, _ := strings.Cut(strings.TrimPrefix(key, tag+p.Sep), p.Sep)
+		if idx, err := strconv.Atoi(index); err == nil && (idx > last || idx == last && key < name) {
+			last, name = idx, key
+		}
+	}
+
+	return last, name
+}
+
+// deleteItems returns a new slice without the deleted indexes. The indexes are in order.
+func deleteItems(field reflect.Value, deleted []int) reflect.Value {
+	value := reflect.MakeSlice(field.Type(), 0, field.Len()-len(deleted))
+
+	for idx := range field.Len() {
+		if len(deleted) > 0 && deleted[0] == idx {
+			deleted = deleted[1:]
+			continue
+		}
+
+		value = reflect.Append(value, field.Index(idx))
+	}
+
+	return value
+}
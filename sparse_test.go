@@ -0,0 +1,94 @@
+package cnfg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type sparseConfig struct {
+	Users []string      `xml:"users"`
+	Hosts []*sparseHost `xml:"hosts"`
+	Ports []int         `xml:"ports"`
+}
+
+type sparseHost struct {
+	Name string `xml:"name"`
+}
+
+func TestSparse(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	pairs := map[string]string{"APP_USERS_4": "you", "APP_HOSTS_1_NAME": "web"}
+	config := &sparseConfig{Users: []string{"a", "b"}}
+
+	_, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.Equal([]string{"a", "b"}, config.Users, "sparse indexes must be ignored by default")
+	assert.Empty(config.Hosts)
+
+	_, err = (&cnfg.ENV{Pfx: "APP", Sparse: cnfg.SparseFill}).UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.Equal([]string{"a", "b", "", "", "you"}, config.Users)
+	assert.Equal([]*sparseHost{nil, {Name: "web"}}, config.Hosts)
+
+	config = &sparseConfig{Users: []string{"a", "b"}}
+	_, err = (&cnfg.ENV{Pfx: "APP", Sparse: cnfg.SparseError}).UnmarshalMap(pairs, config)
+	require.ErrorIs(t, err, cnfg.ErrSliceIndex)
+
+	var fieldErr *cnfg.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal("APP_USERS_4", fieldErr.Var)
+}
+
+func TestSliceLenAndDelete(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	defaults := []string{"a", "b", "c", "d"}
+	config := &sparseConfig{Users: defaults, Ports: []int{1, 2}, Hosts: []*sparseHost{{Name: "x"}, {Name: "y"}}}
+	env := &cnfg.ENV{Pfx: "APP", Delete: "-", Strict: true}
+
+	_, err := env.UnmarshalMap(map[string]string{
+		"APP_USERS_LEN":    "3",
+		"APP_USERS_0":      "-",
+		"APP_USERS_2":      "C",
+		"APP_USERS_3":      "e",
+		"APP_USERS_4":      "-",
+		"APP_USERS_5":      "f",
+		"APP_PORTS_LEN":    "0",
+		"APP_PORTS_0":      "80",
+		"APP_HOSTS_0":      "-",
+		"APP_HOSTS_1_NAME": "z",
+	}, config)
+	require.NoError(t, err)
+	assert.Equal([]string{"b", "C", "e", "f"}, config.Users, "indexes must refer to items before deletion")
+	assert.Equal("d", defaults[3], "appended items must not overwrite truncated items")
+	assert.Equal([]int{80}, config.Ports, "a zero length must replace the slice")
+	assert.Equal([]*sparseHost{{Name: "z"}}, config.Hosts)
+
+	_, err = env.UnmarshalMap(map[string]string{"APP_PORTS_LEN": "3"}, config)
+	require.NoError(t, err)
+	assert.Equal([]int{80, 0, 0}, config.Ports, "a longer length must add zero values")
+
+	_, err = env.UnmarshalMap(map[string]string{"APP_PORTS_LEN": "-1"}, config)
+	require.Error(t, err)
+	assert.Contains(err.Error(), "APP_PORTS_LEN")
+
+	_, err = env.UnmarshalMap(map[string]string{"APP_PORTS_LEN": "9223372036854775807"}, config)
+	require.ErrorIs(t, err, cnfg.ErrSliceLen, "huge lengths must not be allocated")
+	assert.Len(config.Ports, 3)
+
+	_, err = (&cnfg.ENV{Pfx: "APP", Sparse: cnfg.SparseFill}).UnmarshalMap(
+		map[string]string{"APP_PORTS_999999999": "1"}, config)
+	require.ErrorIs(t, err, cnfg.ErrSliceLen, "huge indexes must not be filled")
+
+	var fieldErr *cnfg.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal("APP_PORTS_999999999", fieldErr.Var)
+}
@@ -0,0 +1,291 @@
+package cnfg
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/* This file contains the logic to parse and marshal slices and maps in one variable. */
+
+// SplitSeparator separates slice items and map entries in one variable when the
+// `split` tag option has no delimiter, and ENV.Split is empty: APP_USERS=me,you.
+// Map entries are written as key=value: APP_LABELS=team=ops,env=prod.
+const SplitSeparator = ","
+
+// delim returns the delimiter for a member's slice items and map entries, or an empty
+// string if they are not split. def is ENV.Split.
+func (o tagOpts) delim(def string) string {
+	switch {
+	case o.Delim != "":
+		return o.Delim
+	case o.Split && def == "":
+		return SplitSeparator
+	default:
+		return def
+	}
+}
+
+// splitSlice replaces a slice with the items in its own variable, if the variable exists.
+// Indexed variables are parsed after this, so they may replace or append items.
+func (p *parser) splitSlice(field reflect.Value, tag string) (bool, error) {
+	if p.Delim == "" || !p.Types.leaf(field.Type().Elem()) {
+		return false, nil
+	}
+
+	envval, exists := p.lookup(tag)
+	if !exists {
+		return false, nil
+	}
+
+	items, err := splitList(envval, p.Delim)
+	if err != nil {
+		return false, p.fieldError(field, tag, envval, err)
+	}
+
+	value := reflect.MakeSlice(field.Type(), len(items), len(items))
+
+	for idx, item := range items {
+		restore := p.enter("[" + strconv.Itoa(idx) + "]")
+		_, err := p.Anything(value.Index(idx), tag, item, true, false)
+
+		restore()
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	field.Set(value)
+
+	return true, nil
+}
+
+// splitMap replaces a map with the entries in its own variable, if the variable exists.
+// Variables for each key are parsed after this, so they may replace or add entries.
+func (p *parser) splitMap(field reflect.Value, tag string) (bool, error) {
+	if p.Delim == "" || !p.Types.leaf(field.Type().Elem()) {
+		return false, nil
+	}
+
+	envval, exists := p.lookup(tag)
+	if !exists {
+		return false, nil
+	}
+
+	entries, err := splitEntries(envval, p.Delim)
+	if err != nil {
+		return false, p.fieldError(field, tag, envval, err)
+	}
+
+	value := reflect.MakeMapWithSize(field.Type(), len(entries))
+
+	for _, entry := range entries {
+		key, val := entry[0], entry[1]
+		restore := p.enter("[" + key + "]")
+		keyval := reflect.New(field.Type().Key()).Elem()
+		valval := reflect.New(field.Type().Elem()).Elem()
+
+		err := p.parseKey(keyval, tag, key)
+		if err == nil {
+			_, err = p.Anything(valval, tag, val, true, false)
+		}
+
+		restore()
+
+		if err != nil {
+			return false, err
+		}
+
+		value.SetMapIndex(keyval, valval)
+	}
+
+	field.Set(value)
+
+	return true, nil
+}
+
+// joinSlice marshals a slice into one variable, if it has a delimiter.
+func (p *unparser) joinSlice(field reflect.Value, tag string) (Pairs, bool, error) {
+	if p.Delim == "" || !p.Types.leaf(field.Type().Elem()) {
+		return nil, false, nil
+	}
+
+	items := make([]string, field.Len())
+
+	for idx := range items {
+		item, err := p.leafValue(field.Index(idx), tag)
+		if err != nil {
+			return nil, true, err
+		}
+
+		items[idx] = item
+	}
+
+	p.Keys = append(p.Keys, tag)
+
+	return Pairs{tag: joinList(items, p.Delim)}, true, nil
+}
+
+// joinMap marshals a map into one variable, if it has a delimiter. Keys are sorted.
+func (p *unparser) joinMap(field reflect.Value, tag string, keys []reflect.Value) (Pairs, bool, error) {
+	if p.Delim == "" || !p.Types.leaf(field.Type().Elem()) {
+		return nil, false, nil
+	}
+
+	items := make([]string, len(keys))
+
+	for idx, key := range keys {
+		val, err := p.leafValue(field.MapIndex(key), tag)
+		if err != nil {
+			return nil, true, err
+		}
+
+		items[idx] = quoteItem(fmt.Sprint(key), p.Delim, "=") + "=" + quoteItem(val, p.Delim)
+	}
+
+	p.Keys = append(p.Keys, tag)
+
+	return Pairs{tag: strings.Join(items, p.Delim)}, true, nil
+}
+
+// leafValue marshals a value that fits in one variable, and returns the value.
+func (p *unparser) leafValue(field reflect.Value, tag string) (string, error) {
+	keys := len(p.Keys)
+	output, err := p.Anything(addressable(field), tag, false)
+	p.Keys = p.Keys[:keys] // The caller adds the key.
+
+	return output[tag], err
+}
+
+// splitList splits a delimited list. Items with the delimiter or a double quote in them
+// are wrapped in double quotes, with backslash escapes for \ and ". An empty list has no
+// items, so a list with one empty item is written as "".
+func splitList(list, delim string) ([]string, error) {
+	items := []string{}
+
+	for rest := list; rest != ""; {
+		item, after, err := nextField(rest, delim)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+
+		if rest = strings.TrimPrefix(after, delim); after != "" && rest == "" {
+			items = append(items, "") // A trailing delimiter is an empty item.
+		}
+	}
+
+	return items, nil
+}
+
+// splitEntries splits a delimited list of key=value map entries. Keys are quoted like
+// list items, and also when they have an equal sign in them: "a=b"=c. Values may have
+// equal signs in them without quotes.
+func splitEntries(list, delim string) ([][2]string, error) {
+	entries := [][2]string{}
+
+	for rest := list; rest != ""; {
+		key, after, err := nextField(rest, "=", delim)
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasPrefix(after, "=") {
+			return nil, fmt.Errorf("%w: entry has no '=': %s", ErrSplit, key)
+		}
+
+		val, after, err := nextField(after[1:], delim)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, [2]string{key, val})
+
+		if rest = strings.TrimPrefix(after, delim); after != "" && rest == "" {
+			return nil, fmt.Errorf("%w: empty entry after the last delimiter", ErrSplit)
+		}
+	}
+
+	return entries, nil
+}
+
+// nextField reads a quoted field, or a field up to the first of the stops.
+// The rest of the text starts at the stop.
+func nextField(text string, stops ...string) (string, string, error) {
+	if !strings.HasPrefix(text, `"`) {
+		end := len(text)
+
+		for _, stop := range stops {
+			if idx := strings.Index(text, stop); idx >= 0 && idx < end {
+				end = idx
+			}
+		}
+
+		return text[:end], text[end:], nil
+	}
+
+	field, after, err := unquoteItem(text[1:])
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, stop := range stops {
+		if after == "" || strings.HasPrefix(after, stop) {
+			return field, after, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: text after a quoted item: %s", ErrSplit, after)
+}
+
+// unquoteItem reads a quoted item up to the closing quote, and returns the rest.
+func unquoteItem(quoted string) (string, string, error) {
+	var item strings.Builder
+
+	for idx := 0; idx < len(quoted); idx++ {
+		switch char := quoted[idx]; {
+		case char == '"':
+			return item.String(), quoted[idx+1:], nil
+		case char == '\\' && idx+1 < len(quoted):
+			idx++
+			item.WriteByte(quoted[idx])
+		default:
+			item.WriteByte(char)
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: missing closing quote", ErrSplit)
+}
+
+// joinList joins items with a delimiter, and quotes the items that need it.
+// A list with one empty item is quoted, so it is not parsed as an empty list.
+func joinList(items []string, delim string) string {
+	if len(items) == 1 && items[0] == "" {
+		return `""`
+	}
+
+	quoted := make([]string, len(items))
+	for idx, item := range items {
+		quoted[idx] = quoteItem(item, delim)
+	}
+
+	return strings.Join(quoted, delim)
+}
+
+// quoteItem wraps an item in double quotes if it has the delimiter, a double quote
+// or one of the special strings in it.
+func quoteItem(item, delim string, special ...string) string {
+	needed := strings.Contains(item, delim) || strings.Contains(item, `"`)
+	for _, str := range special {
+		needed = needed || strings.Contains(item, str)
+	}
+
+	if !needed {
+		return item
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item) + `"`
+}
@@ -0,0 +1,119 @@
+package cnfg_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type splitConfig struct {
+	Users  []string          `xml:"users,split"`
+	Ports  []int             `xml:"ports,split=;"`
+	Waits  []time.Duration   `xml:"waits,split"`
+	Labels map[string]string `xml:"labels,split=;"`
+	Hosts  []string          `xml:"hosts"`
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := &cnfg.ENV{Pfx: "APP", Strict: true}
+	config := &splitConfig{Users: []string{"old"}}
+
+	_, err := env.UnmarshalMap(map[string]string{
+		"APP_USERS":    `me,"you, too","say \"hi\"",them`,
+		"APP_USERS_3":  "they",
+		"APP_USERS_4":  "us",
+		"APP_PORTS":    "80;0x1bb",
+		"APP_WAITS":    "1s,1m",
+		"APP_LABELS":   "a=1;b=x=y",
+		"APP_LABELS_c": "3",
+		"APP_HOSTS":    "a,b",
+		"APP_HOSTS_0":  "c",
+	}, config)
+
+	var unknown *cnfg.UnknownError
+
+	require.ErrorAs(t, err, &unknown, "members without split must not accept the compact form")
+	assert.Equal("APP_HOSTS", unknown.Vars[0].Name)
+	assert.Len(unknown.Vars, 1)
+	assert.Equal([]string{"me", "you, too", `say "hi"`, "they", "us"}, config.Users,
+		"indexed items must replace and append")
+	assert.Equal([]int{80, 443}, config.Ports)
+	assert.Equal([]time.Duration{time.Second, time.Minute}, config.Waits)
+	assert.Equal(map[string]string{"a": "1", "b": "x=y", "c": "3"}, config.Labels)
+	assert.Equal([]string{"c"}, config.Hosts, "members without split must not read the compact form")
+
+	marshaled, err := env.Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{
+		"APP_USERS":   `me,"you, too","say \"hi\"",they,us`,
+		"APP_PORTS":   "80;443",
+		"APP_WAITS":   "1s,1m0s",
+		"APP_LABELS":  "a=1;b=x=y;c=3",
+		"APP_HOSTS_0": "c",
+	}, marshaled)
+	require.NoError(t, env.RoundTrip(config))
+
+	vars, err := env.Describe(config)
+	require.NoError(t, err)
+	assert.Equal("APP_USERS", vars[0].Name)
+	assert.Equal("APP_USERS_LEN", vars[1].Name)
+	assert.Equal("APP_USERS_{n}", vars[2].Name)
+
+	_, err = env.UnmarshalMap(map[string]string{"APP_USERS": `"open`}, &splitConfig{})
+	require.ErrorIs(t, err, cnfg.ErrSplit)
+	_, err = env.UnmarshalMap(map[string]string{"APP_LABELS": "a"}, &splitConfig{})
+	require.ErrorIs(t, err, cnfg.ErrSplit)
+}
+
+func TestSplitDefault(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := &cnfg.ENV{Pfx: "APP", Split: " "}
+	config := &splitConfig{}
+
+	_, err := env.UnmarshalMap(map[string]string{
+		"APP_USERS":  "me you",
+		"APP_PORTS":  "80;443",
+		"APP_HOSTS":  "a b",
+		"APP_LABELS": "a=1;b=2",
+	}, config)
+	require.NoError(t, err)
+	assert.Equal([]string{"me", "you"}, config.Users, "ENV.Split must apply to split without a delimiter")
+	assert.Equal([]int{80, 443}, config.Ports, "the tag delimiter must win")
+	assert.Equal([]string{"a", "b"}, config.Hosts, "ENV.Split must apply to every slice")
+	assert.Equal(map[string]string{"a": "1", "b": "2"}, config.Labels)
+
+	marshaled, err := env.Marshal(config)
+	require.NoError(t, err)
+	assert.Equal("a b", marshaled["APP_HOSTS"])
+}
+
+func TestSplitQuoting(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	env := &cnfg.ENV{Pfx: "APP"}
+	config := &splitConfig{Users: []string{""}, Labels: map[string]string{"a=b": "c", "d": `e=f;"g"`}}
+
+	pairs, err := env.Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(`""`, pairs["APP_USERS"], "one empty item must not be written as an empty list")
+	assert.Equal(`"a=b"=c;d="e=f;\"g\""`, pairs["APP_LABELS"], "keys with an equal sign must be quoted")
+
+	parsed := &splitConfig{}
+	_, err = env.UnmarshalMap(pairs, parsed)
+	require.NoError(t, err)
+	assert.Equal(config.Users, parsed.Users)
+	assert.Equal(config.Labels, parsed.Labels)
+	require.NoError(t, env.RoundTrip(config))
+
+	_, err = env.UnmarshalMap(map[string]string{"APP_LABELS": `"a"b=c`}, &splitConfig{})
+	require.ErrorIs(t, err, cnfg.ErrSplit)
+}
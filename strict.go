@@ -0,0 +1,82 @@
+package cnfg
+
+import (
+	"slices"
+	"strings"
+)
+
+/* This file contains the logic to find env variables that were not parsed. */
+
+// unknown returns the prefixed variables that were not used by any struct member.
+// Each is paired with the nearest variable name the struct accepts.
+func (p *parser) unknown(prefix string) []UnknownVar {
+	if prefix == "" {
+		return nil
+	}
+
+	unknown := []UnknownVar{}
+
+	for key := range p.Vals {
+		if !p.Used[key] && strings.HasPrefix(key, prefix+p.Sep) {
+			unknown = append(unknown, UnknownVar{Name: key, Suggest: p.suggest(key)})
+		}
+	}
+
+	slices.SortFunc(unknown, func(a, b UnknownVar) int { return strings.Compare(a.Name, b.Name) })
+
+	return unknown
+}
+
+// suggest returns the accepted variable name nearest to the provided name.
+// Nothing is returned if the nearest name is not very near. Ties prefer
+// names that were not provided, then sort alphabetically.
+func (p *parser) suggest(name string) string {
+	const divisor = 4 // Allow 1 edit for every 4 characters.
+
+	suggest, best := "", len(name)/divisor+1
+
+	for accepted := range p.Accepted {
+		dist := editDistance(strings.ToUpper(name), strings.ToUpper(accepted))
+		if dist < best || (dist == best && suggest != "" && p.better(accepted, suggest)) {
+			suggest, best = accepted, dist
+		}
+	}
+
+	return suggest
+}
+
+// better breaks a tie between two suggestions.
+func (p *parser) better(name, than string) bool {
+	if p.Used[name] != p.Used[than] {
+		return !p.Used[name]
+	}
+
+	return name < than
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(from, to string) int {
+	prev := make([]int, len(to)+1)
+	curr := make([]int, len(to)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(from); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(to); j++ {
+			cost := 1
+			if from[i-1] == to[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(to)]
+}
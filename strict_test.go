@@ -0,0 +1,59 @@
+package cnfg_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+
+	type strictConfig struct {
+		Shelter struct {
+			Title string `xml:"title"`
+		} `xml:"shelter"`
+		Users  []string          `xml:"users"`
+		Labels map[string]string `xml:"labels"`
+		Port   int               `xml:"port,default=80"`
+	}
+
+	assert := assert.New(t)
+	pairs := map[string]string{
+		"APP_SHELTR_TITLE": "typo",
+		"APP_SHELTER":      "struct",
+		"APP_USERS":        "not,split",
+		"APP_USERS_0":      "me",
+		"APP_USERS_5":      "skipped",
+		"APP_LABELS_team":  "ops",
+		"APP_PROT":         "8080",
+		"APP_NOTHING_LIKE": "this",
+		"OTHER_VAR":        "ignored",
+	}
+	config := &strictConfig{}
+
+	worked, err := (&cnfg.ENV{Pfx: "APP", Strict: true}).UnmarshalMap(pairs, config)
+	require.ErrorIs(t, err, cnfg.ErrUnknown)
+	assert.False(worked)
+	assert.Equal([]string{"me"}, config.Users, "the struct must be parsed even with unknown variables")
+	assert.Equal("ops", config.Labels["team"])
+
+	var unknown *cnfg.UnknownError
+
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal([]cnfg.UnknownVar{
+		{Name: "APP_NOTHING_LIKE"},
+		{Name: "APP_PROT", Suggest: "APP_PORT"},
+		{Name: "APP_SHELTER"}, // Structs and slices are not parsed from their own variable.
+		{Name: "APP_SHELTR_TITLE", Suggest: "APP_SHELTER_TITLE"},
+		{Name: "APP_USERS", Suggest: "APP_USERS_1"},
+		{Name: "APP_USERS_5", Suggest: "APP_USERS_1"},
+	}, unknown.Vars)
+	assert.Contains(err.Error(), "APP_PROT (did you mean APP_PORT?)")
+
+	worked, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, config)
+	require.NoError(t, err, "unknown variables must be ignored when not strict")
+	assert.True(worked)
+}
@@ -0,0 +1,82 @@
+package cnfg
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/* This file contains the logic to parse struct tag options. */
+
+// DefaultSeparator splits a tag default into slice items and map entries.
+// Map entries are written as key=value. ie. `xml:"users,default=me;you"`.
+const DefaultSeparator = ";"
+
+// tagOpts are the comma separated options that follow the name in a struct tag.
+type tagOpts struct {
+	Delenv    bool   // delete the env variable after it's parsed.
+	Omitempty bool   // do not marshal zero values.
+	Required  bool   // return an error if nothing provides a value.
+	Secret    bool   // redact the value when marshaling with ENV.Redact.
+	Size      bool   // integers are byte sizes, like 10MiB.
+	Split     bool   // slices and maps may be in one variable.
+	Delim     string // delimiter for Split, from split=delim.
+	Default   string // value to parse when nothing else provides one.
+}
+
+// parseTag splits a struct tag into its name and options.
+func parseTag(tag string) (string, tagOpts) {
+	split := strings.Split(tag, ",")
+	opts := tagOpts{}
+
+	for _, opt := range split[1:] {
+		switch {
+		case opt == "delenv":
+			opts.Delenv = true
+		case opt == "omitempty":
+			opts.Omitempty = true
+		case opt == "required":
+			opts.Required = true
+		case opt == "secret":
+			opts.Secret = true
+		case opt == "size":
+			opts.Size = true
+		case opt == "split":
+			opts.Split = true
+		case strings.HasPrefix(opt, "split="):
+			opts.Split, opts.Delim = true, strings.TrimPrefix(opt, "split=")
+		case strings.HasPrefix(opt, "default="):
+			opts.Default = strings.TrimPrefix(opt, "default=")
+		}
+	}
+
+	return split[0], opts
+}
+
+// defaultPairs turns a tag default into the env variables it represents.
+// This allows defaults to go through the same parser as env variables.
+func defaultPairs(typ reflect.Type, tag, value, sep string) Pairs {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	pairs := Pairs{}
+
+	switch {
+	case isLeaf(typ): // This includes []byte, byte arrays and TextUnmarshalers.
+		pairs[tag] = value
+	case typ.Kind() == reflect.Slice, typ.Kind() == reflect.Array:
+		for idx, item := range strings.Split(value, DefaultSeparator) {
+			pairs[strings.Join([]string{tag, strconv.Itoa(idx)}, sep)] = item
+		}
+	case typ.Kind() == reflect.Map:
+		for _, entry := range strings.Split(value, DefaultSeparator) {
+			key, val, _ := strings.Cut(entry, "=")
+			pairs[strings.Join([]string{tag, key}, sep)] = val
+		}
+	default:
+		pairs[tag] = value
+	}
+
+	return pairs
+}
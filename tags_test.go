@@ -0,0 +1,126 @@
+package cnfg_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golift.io/cnfg"
+)
+
+type defaultTest struct {
+	Name    string            `xml:"name,default=golift"`
+	Timeout time.Duration     `xml:"timeout,default=30s"`
+	Dur     cnfg.Duration     `xml:"dur,default=1m"`
+	Count   *int              `xml:"count,default=5"`
+	IP      net.IP            `xml:"ip,default=127.0.0.1"`
+	Users   []string          `xml:"users,default=me;you;them"`
+	Levels  map[string]int    `xml:"levels,default=low=1;high=9"`
+	Labels  map[string]string `xml:"labels"`
+	Sub     struct {
+		Enabled bool `xml:"enabled,default=true"`
+	} `xml:"sub"`
+}
+
+func TestDefaults(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	config := &defaultTest{}
+
+	worked, err := cnfg.UnmarshalMap(map[string]string{}, config)
+	require.NoError(t, err)
+	assert.False(worked, "defaults are not env variables and must not be reported as found")
+	assert.Equal("golift", config.Name)
+	assert.Equal(30*time.Second, config.Timeout)
+	assert.Equal(time.Minute, config.Dur.Duration)
+	require.NotNil(t, config.Count)
+	assert.Equal(5, *config.Count)
+	assert.Equal("127.0.0.1", config.IP.String())
+	assert.Equal([]string{"me", "you", "them"}, config.Users)
+	assert.Equal(map[string]int{"low": 1, "high": 9}, config.Levels)
+	assert.Nil(config.Labels, "a member without a default must not be changed")
+	assert.True(config.Sub.Enabled)
+}
+
+func TestDefaultsOverridden(t *testing.T) {
+	t.Parallel()
+
+	assert := assert.New(t)
+	config := &defaultTest{Name: "existing", Users: []string{"one"}}
+	pairs := map[string]string{
+		"APP_TIMEOUT":    "1h",
+		"APP_LEVELS_mid": "5",
+	}
+
+	worked, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.True(worked)
+	assert.Equal("existing", config.Name, "an existing value must not be replaced by a default")
+	assert.Equal(time.Hour, config.Timeout, "an env variable must not be replaced by a default")
+	assert.Equal([]string{"one"}, config.Users)
+	assert.Equal(map[string]int{"mid": 5}, config.Levels)
+}
+
+func TestDefaultsInvalid(t *testing.T) {
+	t.Parallel()
+
+	type invalid struct {
+		Timeout time.Duration `xml:"timeout,default=thirty"`
+	}
+
+	worked, err := cnfg.UnmarshalMap(map[string]string{}, &invalid{})
+	require.Error(t, err, "an invalid default must return a parse error")
+	assert.False(t, worked)
+}
+
+func TestRequired(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Name string `xml:"name,required"`
+		Pass string `xml:"pass"`
+	}
+
+	type required struct {
+		Host  string `xml:"host,required"`
+		Port  int    `xml:"port,required,default=80"`
+		Token string `xml:"token,required"`
+		DB    struct {
+			Password string `xml:"password,required"`
+		} `xml:"db"`
+		Users []user `xml:"user"`
+		TLS   *struct {
+			Cert string `xml:"cert,required"`
+			Key  string `xml:"key"`
+		} `xml:"tls"`
+	}
+
+	assert := assert.New(t)
+	config := &required{Host: "existing", Users: []user{{Pass: "nameless"}}}
+
+	worked, err := (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(map[string]string{}, config)
+	require.ErrorIs(t, err, cnfg.ErrRequired)
+	assert.False(worked)
+
+	var missing *cnfg.MissingError
+
+	require.True(t, errors.As(err, &missing))
+	assert.Equal([]string{"APP_TOKEN", "APP_DB_PASSWORD", "APP_USER_0_NAME"}, missing.Vars,
+		"every missing variable must be returned, and non-existent slice items are not missing")
+	assert.Equal(80, config.Port, "a default satisfies a required member")
+	assert.Nil(config.TLS, "members of a nil pointer are not missing")
+
+	pairs := map[string]string{"APP_TOKEN": "abc", "APP_DB_PASSWORD": "secret", "APP_USER_0_NAME": "me"}
+	worked, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, config)
+	require.NoError(t, err)
+	assert.True(worked)
+
+	pairs["APP_TLS_KEY"] = "key.pem"
+	_, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, config)
+	require.True(t, errors.As(err, &missing))
+	assert.Equal([]string{"APP_TLS_CERT"}, missing.Vars, "members of a created pointer may be missing")
+}
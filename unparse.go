@@ -1,9 +1,11 @@
 package cnfg
 
 import (
+	"cmp"
 	"encoding"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,8 +14,17 @@ import (
 /* This file contains the methods that convert a struct into environment variables. */
 
 type unparser struct {
-	Low bool   // Allow lowercase values in env variable names.
-	Tag string // struct tag to look for on struct members
+	Low    bool     // Allow lowercase values in env variable names.
+	Tag    string   // struct tag to look for on struct members
+	Redact bool     // Replace secret values with a placeholder.
+	Types  registry // Concrete types for interface members.
+	Namer  NameFunc // Names members without a tag name.
+	Sep    string   // Level separator.
+	Size   bool     // Format integers as byte sizes.
+	Split  string   // Default delimiter for slices and maps in one variable.
+	Delim  string   // Delimiter for the current member's slices and maps.
+	// Keys are the variable names in the order they were created.
+	Keys []string
 }
 
 func (p *unparser) DeconStruct(field reflect.Value, prefix string) (Pairs, error) { //nolint:cyclop
@@ -21,38 +32,27 @@ func (p *unparser) DeconStruct(field reflect.Value, prefix string) (Pairs, error
 
 	element := field.Type().Elem()
 	for idx := range element.NumField() { // Loop each struct member
-		tagval := strings.Split(element.Field(idx).Tag.Get(p.Tag), ",")
-		tag := tagval[0]
-
-		if !p.Low {
-			tag = strings.ToUpper(tagval[0]) // like "NAME" or "TIMEOUT"
-		}
-
+		tag, opts := memberName(element.Field(idx), p.Tag, p.Low, p.Namer)
 		if !field.Elem().Field(idx).CanSet() || tag == "-" {
 			continue
 		}
 
-		if tag == "" && !element.Field(idx).Anonymous {
-			tag = element.Field(idx).Name
-			if !p.Low {
-				tag = strings.ToUpper(element.Field(idx).Name)
-			}
-		}
+		tag = strings.Trim(strings.Join([]string{prefix, tag}, p.Sep), p.Sep)
 
-		tag = strings.Trim(strings.Join([]string{prefix, tag}, LevelSeparator), LevelSeparator)
-		omitempty := false
+		size, delim := p.Size, p.Delim
+		p.Size, p.Delim = opts.Size, opts.delim(p.Split)
 
-		for i := 1; i < len(tagval); i++ {
-			if tagval[i] == "omitempty" {
-				omitempty = true
-			}
-		}
+		o, err := p.Anything(field.Elem().Field(idx), tag, opts.Omitempty)
+		p.Size, p.Delim = size, delim
 
-		o, err := p.Anything(field.Elem().Field(idx), tag, omitempty)
 		if err != nil {
 			return nil, err
 		}
 
+		if opts.Secret && p.Redact {
+			o.redact() // This includes every nested struct, slice and map value.
+		}
+
 		output.Merge(o)
 	}
 
@@ -64,8 +64,15 @@ func (p *unparser) Anything(field reflect.Value, tag string, omitempty bool) (Pa
 		return Pairs{}, nil
 	}
 
+	if field.Kind() == reflect.Interface {
+		if output, exists, err := p.Concrete(field, tag, omitempty); err != nil || exists {
+			return output, err
+		}
+	}
+
 	output, exists, err := p.Interface(field, tag, omitempty)
 	if err != nil || exists {
+		p.Keys = append(p.Keys, output.SortedKeys()...)
 		return output, err
 	}
 
@@ -81,10 +88,24 @@ func (p *unparser) Anything(field reflect.Value, tag string, omitempty bool) (Pa
 		return p.DeconStruct(field.Addr(), tag)
 	case reflect.Slice:
 		return p.Slice(field, tag, omitempty)
+	case reflect.Array:
+		return p.Array(field, tag, omitempty)
 	case reflect.Map:
 		return p.Map(field, tag, omitempty)
+	case reflect.Interface:
+		if field.IsNil() {
+			return output, nil
+		} else if field.Type() != reflect.TypeFor[error]() {
+			// Pass the value in the interface back into the start.
+			return p.Anything(addressable(field.Elem()), tag, omitempty)
+		}
+
+		fallthrough
 	default:
-		return p.Member(field, tag, omitempty)
+		output, err := p.Member(field, tag, omitempty)
+		p.Keys = append(p.Keys, output.SortedKeys()...)
+
+		return output, err
 	}
 }
 
@@ -142,6 +163,11 @@ func (p *unparser) Interface(field reflect.Value, tag string, omitempty bool) (P
 func (p *unparser) Member(field reflect.Value, tag string, omitempty bool) (Pairs, error) { //nolint:cyclop
 	output := Pairs{}
 
+	if p.Size && isInteger(field.Kind()) {
+		output.Set(tag, formatSize(field))
+		return output, nil
+	}
+
 	switch val := field.Interface().(type) {
 	// Handle each member type appropriately (differently).
 	case error:
@@ -174,6 +200,10 @@ func (p *unparser) Member(field reflect.Value, tag string, omitempty bool) (Pair
 		output.Set(tag, strconv.FormatFloat(val, 'f', -1, bits64))
 	case float32:
 		output.Set(tag, strconv.FormatFloat(float64(val), 'f', -1, bits32))
+	case complex128:
+		output.Set(tag, strconv.FormatComplex(val, 'f', -1, bits128))
+	case complex64:
+		output.Set(tag, strconv.FormatComplex(complex128(val), 'f', -1, bits64))
 	case time.Duration:
 		output.Set(tag, (time.Duration(field.Int()) * time.Nanosecond).String())
 	case bool:
@@ -192,17 +222,14 @@ func (p *unparser) kindMember(field reflect.Value, tag string, _ bool) (Pairs, e
 	case reflect.String:
 		output.Set(tag, field.String())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		val, _ := field.Interface().(uint64)
-		output.Set(tag, strconv.FormatUint(val, base10))
+		// Named types, like `type Port uint16`, do not type-assert to their kind.
+		output.Set(tag, strconv.FormatUint(field.Uint(), base10))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		val, _ := field.Interface().(int64)
-		output.Set(tag, strconv.FormatInt(val, base10))
-	case reflect.Float64:
-		val, _ := field.Interface().(float64)
-		output.Set(tag, strconv.FormatFloat(val, 'f', -1, bits64))
-	case reflect.Float32:
-		val, _ := field.Interface().(float32)
-		output.Set(tag, strconv.FormatFloat(float64(val), 'f', -1, bits32))
+		output.Set(tag, strconv.FormatInt(field.Int(), base10))
+	case reflect.Float64, reflect.Float32:
+		output.Set(tag, strconv.FormatFloat(field.Float(), 'f', -1, field.Type().Bits()))
+	case reflect.Complex128, reflect.Complex64:
+		output.Set(tag, strconv.FormatComplex(field.Complex(), 'f', -1, field.Type().Bits()))
 	case reflect.Bool:
 		output.Set(tag, strconv.FormatBool(field.Bool()))
 	}
@@ -216,19 +243,47 @@ func (p *unparser) Slice(field reflect.Value, tag string, omitempty bool) (Pairs
 	// slice of bytes works differently than any other slice type.
 	if field.Type().String() == "[]uint8" {
 		output.Set(tag, string(field.Bytes()))
+		p.Keys = append(p.Keys, tag)
 
 		return output, nil
 	}
 
+	if output, ok, err := p.joinSlice(field, tag); ok {
+		return output, err
+	}
+
 	return p.SliceValue(field, tag, omitempty)
 }
 
+// Array deconstructs fixed-size arrays. Byte arrays become a single variable,
+// without trailing zero bytes. Other arrays use one variable per index.
+func (p *unparser) Array(field reflect.Value, tag string, omitempty bool) (Pairs, error) {
+	if !field.CanAddr() {
+		value := reflect.New(field.Type()).Elem()
+		value.Set(field)
+		field = value
+	}
+
+	if field.Type().Elem().Kind() != reflect.Uint8 {
+		return p.SliceValue(field, tag, omitempty)
+	}
+
+	bytes := make([]byte, field.Len())
+	for idx := range bytes {
+		bytes[idx] = byte(field.Index(idx).Uint())
+	}
+
+	p.Keys = append(p.Keys, tag)
+
+	return Pairs{tag: strings.TrimRight(string(bytes), "\x00")}, nil
+}
+
 func (p *unparser) SliceValue(field reflect.Value, tag string, omitempty bool) (Pairs, error) {
 	output := Pairs{}
 
 	total := field.Len()
 	for i := range total {
-		ntag := strings.Join([]string{tag, strconv.Itoa(i)}, LevelSeparator)
+		ntag := strings.Join([]string{tag, strconv.Itoa(i)}, p.Sep)
 		value := reflect.Indirect(field.Index(i).Addr())
 
 		o, err := p.Anything(value, ntag, omitempty)
@@ -245,10 +300,23 @@ func (p *unparser) SliceValue(field reflect.Value, tag string, omitempty bool) (
 func (p *unparser) Map(field reflect.Value, tag string, omitempty bool) (Pairs, error) {
 	output := Pairs{}
 
-	for i := field.MapRange(); i.Next(); {
-		ntag := fmt.Sprintf("%s%s%v", tag, LevelSeparator, i.Key())
+	keys := field.MapKeys()
+	slices.SortFunc(keys, compareKeys) // Sort the keys so the output order is consistent.
+
+	if output, ok, err := p.joinMap(field, tag, keys); ok {
+		return output, err
+	}
 
-		o, err := p.Anything(i.Value(), ntag, omitempty)
+	if list, ok := p.keysList(field.Type(), keys); ok {
+		ktag := specialVar(tag, p.Sep, KeysVar, p.Low)
+		output.Set(ktag, list)
+		p.Keys = append(p.Keys, ktag)
+	}
+
+	for _, key := range keys {
+		ntag := fmt.Sprintf("%s%s%v", tag, p.Sep, key)
+
+		o, err := p.Anything(addressable(field.MapIndex(key)), ntag, omitempty)
 		if err != nil {
 			return output, err
 		}
@@ -258,3 +326,32 @@ func (p *unparser) Map(field reflect.Value, tag string, omitempty bool) (Pairs,
 
 	return output, nil
 }
+
+// keysList returns the KeysVar value for a map, if it's needed. It's needed when a
+// key has the separator in it, and the values are not parsed from one variable.
+func (p *unparser) keysList(typ reflect.Type, keys []reflect.Value) (string, bool) {
+	list := make([]string, len(keys))
+	needed := false
+	leaf := p.Types.leaf(typ.Elem())
+
+	for idx, key := range keys {
+		list[idx] = fmt.Sprint(key)
+		needed = needed || (!leaf && strings.Contains(list[idx], p.Sep))
+	}
+
+	return strings.Join(list, KeysSeparator), needed
+}
+
+// compareKeys sorts map keys. Numbers are sorted numerically, and everything else as strings.
+func compareKeys(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
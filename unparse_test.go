@@ -128,7 +128,224 @@ func TestDeconStruct(t *testing.T) {
 
 	for _, v := range pairs.Quoted() {
 		// fmt.Println(v)
-		p := strings.Split(v, "=")
-		assert.Equal(`"`+pairs[p[0]]+`"`, p[1], "returned Slice() value is wrong")
+		key, val, _ := strings.Cut(v, "=")
+		assert.Equal(`'`+pairs[key]+`'`, val, "returned Slice() value is wrong")
 	}
 }
+
+func TestMarshalRedact(t *testing.T) {
+	t.Parallel()
+
+	type creds struct {
+		User string `xml:"user"`
+		Pass string `xml:"pass,secret"`
+	}
+
+	type secretConfig struct {
+		Name   string            `xml:"name"`
+		Token  string            `xml:"token,secret"`
+		Empty  string            `xml:"empty,secret"`
+		Creds  creds             `xml:"creds"`
+		Admin  *creds            `xml:"admin,secret"`
+		Keys   []string          `xml:"keys,secret"`
+		Tokens map[string]string `xml:"tokens,secret"`
+	}
+
+	assert := assert.New(t)
+	config := &secretConfig{
+		Name:   "app",
+		Token:  "abc123",
+		Creds:  creds{User: "me", Pass: "hunter2"},
+		Admin:  &creds{User: "root", Pass: "toor"},
+		Keys:   []string{"key1", "key2"},
+		Tokens: map[string]string{"github": "ghp_xyz"},
+	}
+
+	pairs, err := (&cnfg.ENV{Pfx: "APP", Redact: true}).Marshal(config)
+	require.NoError(t, err)
+	assert.Equal(cnfg.Pairs{
+		"APP_NAME":          "app",
+		"APP_TOKEN":         cnfg.Redacted,
+		"APP_EMPTY":         "",
+		"APP_CREDS_USER":    "me",
+		"APP_CREDS_PASS":    cnfg.Redacted,
+		"APP_ADMIN_USER":    cnfg.Redacted,
+		"APP_ADMIN_PASS":    cnfg.Redacted,
+		"APP_KEYS_0":        cnfg.Redacted,
+		"APP_KEYS_1":        cnfg.Redacted,
+		"APP_TOKENS_github": cnfg.Redacted,
+	}, pairs)
+
+	pairs, err = cnfg.MarshalENV(config, "APP")
+	require.NoError(t, err)
+	assert.Equal("abc123", pairs["APP_TOKEN"], "values must only be redacted when requested")
+	assert.Equal("hunter2", pairs["APP_CREDS_PASS"])
+
+	_, err = (&cnfg.ENV{Pfx: "APP", Redact: true}).UnmarshalMap(map[string]string{"APP_CREDS_PASS": "hunter2"},
+		&struct {
+			Creds struct {
+				Pass int `xml:"pass"`
+			} `xml:"creds,secret"`
+		}{})
+
+	var fieldErr *cnfg.FieldError
+
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(cnfg.Redacted, fieldErr.Value, "secret values must be redacted from parse errors")
+	assert.NotContains(err.Error(), "hunter2", "secret values must be redacted from error messages")
+	require.ErrorIs(t, err, strconv.ErrSyntax, "the parse error must still be wrapped")
+
+	_, err = (&cnfg.ENV{Pfx: "APP", Redact: true}).UnmarshalMap(map[string]string{"APP_PASS": "e"},
+		&struct {
+			Pass int `xml:"pass,secret"`
+		}{})
+	require.EqualError(t, err, "APP_PASS: invalid secret value: invalid syntax",
+		"short secrets must not garble the message")
+}
+
+func TestMarshalOrder(t *testing.T) {
+	t.Parallel()
+
+	type server struct {
+		Port int    `xml:"port"`
+		Host string `xml:"host"`
+	}
+
+	type orderConfig struct {
+		Zeta    string            `xml:"zeta"`
+		Servers []server          `xml:"server"`
+		Labels  map[string]string `xml:"label"`
+		Ports   map[int]string    `xml:"port"`
+		Alpha   string            `xml:"alpha"`
+	}
+
+	assert := assert.New(t)
+	config := &orderConfig{
+		Zeta:    "z",
+		Servers: make([]server, 11),
+		Labels:  map[string]string{"b": "2", "a": "1"},
+		Ports:   map[int]string{10: "ten", 9: "nine"},
+		Alpha:   "a",
+	}
+
+	pairs, keys, err := (&cnfg.ENV{Pfx: "APP"}).MarshalOrder(config)
+	require.NoError(t, err)
+
+	expect := []string{"APP_ZETA"}
+	for i := range 11 {
+		expect = append(expect, "APP_SERVER_"+strconv.Itoa(i)+"_PORT", "APP_SERVER_"+strconv.Itoa(i)+"_HOST")
+	}
+
+	expect = append(expect, "APP_LABEL_a", "APP_LABEL_b", "APP_PORT_9", "APP_PORT_10", "APP_ALPHA")
+	assert.Equal(expect, keys, "keys must be in struct order with numeric indexes and sorted map keys")
+	assert.Len(pairs, len(keys))
+	assert.Equal("APP_ZETA=z", pairs.EnvOrder(keys)[0])
+
+	for range 5 {
+		_, again, err := (&cnfg.ENV{Pfx: "APP"}).MarshalOrder(config)
+		require.NoError(t, err)
+		assert.Equal(keys, again, "the order must be the same every time")
+	}
+}
+
+type (
+	namedString  string
+	namedBool    bool
+	namedInt     int
+	namedInt8    int8
+	namedInt16   int16
+	namedInt32   int32
+	namedInt64   int64
+	namedUint    uint
+	namedUint8   uint8
+	namedUint16  uint16
+	namedUint32  uint32
+	namedUint64  uint64
+	namedFloat32 float32
+	namedFloat64 float64
+	namedComplex complex128
+)
+
+// roundTrip has a member of every kind. Plain uint8 (byte) members are parsed
+// as a single character, so they are left out; named uint8 types are numbers.
+type roundTrip struct {
+	String  string        `xml:"string"`
+	Bool    bool          `xml:"bool"`
+	Int     int           `xml:"int"`
+	Int8    int8          `xml:"int8"`
+	Int16   int16         `xml:"int16"`
+	Int32   int32         `xml:"int32"`
+	Int64   int64         `xml:"int64"`
+	Uint    uint          `xml:"uint"`
+	Uint16  uint16        `xml:"uint16"`
+	Uint32  uint32        `xml:"uint32"`
+	Uint64  uint64        `xml:"uint64"`
+	Float32 float32       `xml:"float32"`
+	Float64 float64       `xml:"float64"`
+	C64     complex64     `xml:"c64"`
+	C128    complex128    `xml:"c128"`
+	Dur     time.Duration `xml:"dur"`
+	Named   struct {
+		String  namedString  `xml:"string"`
+		Bool    namedBool    `xml:"bool"`
+		Int     namedInt     `xml:"int"`
+		Int8    namedInt8    `xml:"int8"`
+		Int16   namedInt16   `xml:"int16"`
+		Int32   namedInt32   `xml:"int32"`
+		Int64   namedInt64   `xml:"int64"`
+		Uint    namedUint    `xml:"uint"`
+		Uint8   namedUint8   `xml:"uint8"`
+		Uint16  namedUint16  `xml:"uint16"`
+		Uint32  namedUint32  `xml:"uint32"`
+		Uint64  namedUint64  `xml:"uint64"`
+		Float32 namedFloat32 `xml:"float32"`
+		Float64 namedFloat64 `xml:"float64"`
+		Complex namedComplex `xml:"complex"`
+	} `xml:"named"`
+	Ptr   *namedUint16           `xml:"ptr"`
+	Ports []namedUint16          `xml:"ports"`
+	Array [2]namedInt8           `xml:"array"`
+	Map   map[string]namedUint32 `xml:"map"`
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	port := namedUint16(8080)
+	config := &roundTrip{
+		String: "str", Bool: true, Int: -1, Int8: -128, Int16: -32768, Int32: -2147483648,
+		Int64: -9223372036854775808, Uint: 1, Uint16: 65535, Uint32: 4294967295, Uint64: 18446744073709551615,
+		Float32: 3.25, Float64: -0.000123, C64: 1 + 2i, C128: -3.5i, Dur: time.Minute,
+		Ptr: &port, Ports: []namedUint16{80, 443}, Array: [2]namedInt8{-1, 127},
+		Map: map[string]namedUint32{"a": 1, "b": 4294967295},
+	}
+	config.Named.String = "named"
+	config.Named.Bool = true
+	config.Named.Int = -2
+	config.Named.Int8 = 127
+	config.Named.Int16 = 32767
+	config.Named.Int32 = 2147483647
+	config.Named.Int64 = 9223372036854775807
+	config.Named.Uint = 3
+	config.Named.Uint8 = 255
+	config.Named.Uint16 = 65535
+	config.Named.Uint32 = 4294967295
+	config.Named.Uint64 = 18446744073709551615
+	config.Named.Float32 = 1.5
+	config.Named.Float64 = 2.000001
+	config.Named.Complex = 1 - 1i
+
+	pairs, err := cnfg.MarshalENV(config, "APP")
+	require.NoError(t, err)
+	assert.Equal(t, "8080", pairs["APP_PTR"], "named types must not be marshaled as zero")
+	assert.Equal(t, "65535", pairs["APP_NAMED_UINT16"])
+	assert.Equal(t, "2.000001", pairs["APP_NAMED_FLOAT64"])
+
+	parsed := &roundTrip{}
+	_, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(pairs, parsed)
+	require.NoError(t, err)
+	assert.Equal(t, config, parsed, "every kind must survive a marshal and unmarshal")
+
+	_, err = (&cnfg.ENV{Pfx: "APP"}).UnmarshalMap(map[string]string{"APP_NAMED_INT8": "128"}, parsed)
+	require.ErrorIs(t, err, strconv.ErrRange, "named types must not overflow")
+}